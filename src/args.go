@@ -6,28 +6,58 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"sort"
+	"time"
+
+	"minfo/pkg/sysinfo"
 )
 
 func usage() {
 	fmt.Printf(`
 Description:
     %s is a tool to display information about the host system.
-    It only works on MacOs.
+    It works on macOS and Linux.
 
 Usage:
-    %s [--config <path>] [-j|--json] [-i|--items] [-v|--version] [-l|--logo <path>]
+    %s [--config <path>] [-j|--json] [-o|--output text|json|prometheus] [-i|--items] [-v|--version] [-l|--logo <path>]
     %s [-r|--refresh[=false]] [-c|--cache[=false]] [-d|--display-logo[=false]] [-n|--nerd-symbols[=false]]
+    %s [-t|--timeout <duration>] [--record <dir>|--replay <dir>] [--strict]
+    %s [--format <template>] [-w|--watch <duration>]
+    %s serve [--listen <address>] [options]
+    %s logo list
+    %s logo convert <image> [--width <characters>] [--charset blocks|ascii|braille]
 
 Options:
     --config <path>             Path to the configuration file (default: %s).
     -d, --display-logo[=false]  Display the ASCII art logo (default: true).
     -n, --nerd-symbols[=false]  Add nerd font symbol for each item title (default: true).
-    -l, --logo[=<path>]         Path to ASCII art logo file
-	                            (default: $HOMEBREW_PREFIX/share/minfo/apple or $HOME/.config/minfo/logo).
+    -l, --logo <name|path>      Name of a built-in logo (see "logo list"), Ex. apple or builtin:apple-nocolor,
+                                or path to a logo file (default: auto, chosen from the model and the OS).
+    --image-logo <path>         PNG or JPEG image displayed instead of the logo, with the kitty, iTerm2
+                                or sixel graphics (if the terminal supports none, the logo is displayed).
     -j, --json[=false]          Display information in JSON instead of plain text (default: false).
+                                Same as --output json.
+    -o, --output <format>       Output format: text, json or prometheus (OpenMetrics text,
+                                Ex. for node_exporter textfile collector) (default: text).
     -c, --cache[=false]         Use cache file (default: true).
     -r, --refresh[=false]       Refresh the cache file (default: false).
+    -t, --timeout <duration>    Overall time given to fetch the information, Ex. 5s (default: 10s).
+                                Items not fetched in time are displayed as "timed out".
+    --strict                    Exit with an error if any item cannot be fetched (default: false).
+                                Otherwise, such items are displayed as "unavailable (reason)"
+                                (and listed in "errors" in JSON).
+    --record <dir>              Record the output of the commands (system_profiler, ioreg...) into <dir>.
+    --replay <dir>              Do not run the commands, but replay their output recorded into <dir>
+                                (Ex. to test the parsing of macOS outputs on another OS).
+    --format <template>         Go text/template used to display the information, instead of the
+                                default "Title: value" lines, Ex. '{{.Cpu.Model}} ({{.Memory.Amount}} GB)'.
+                                Functions bytes, percent, color and nerd are available.
+    -w, --watch <duration>      Display the information again every <duration>, Ex. 5s: the text
+                                is redrawn in place (one JSON object per line with --json).
+                                Only battery, disk, uptime, datetime and weather are fetched again.
+    --listen <address>          Address the server listens on, with "serve" (default: :9870).
+    --width <characters>        Width of the converted logo, with "logo convert" (default: 40).
+    --charset <charset>         Characters of the converted logo, with "logo convert": blocks (half
+                                blocks), ascii or braille (default: blocks).
     -i, --items                 Display all available information to display and exit.
     -v, --version               Show version and exit.
     -h, --help                  Show this help message and exit.
@@ -40,18 +70,60 @@ Command line argument --cache and --display-logo will override the values in the
 
 --refresh=true and --cache=false are mutually exclusive.
 
-If you provide --json=true, then --display-logo will be ignored.
+With "serve", minfo runs an HTTP server which re-collects the items on intervals
+(see "serve:" in the configuration file), and serves /info.json, /metrics (OpenMetrics)
+and /healthz.
+
+"logo list" displays the names and a preview of the built-in logos.
+"logo convert" converts a PNG or JPEG image into a logo (written to the standard output),
+Ex. minfo logo convert company.png --width 30 > ~/.config/minfo/logo.yaml
+("logos" can be used instead of "logo").
+
+--record and --replay do not use the cache file, and are mutually exclusive
+(with each other, and with --refresh=true).
+
+If you provide --json=true (or --output json|prometheus), then --display-logo and --format will be ignored.
 
-`, appName, appName, appName, defaultConfigFile)
+`, appName, appName, appName, appName, appName, appName, appName, appName, defaultConfigFile)
 }
 
+// Commands (first argument) and their subcommands
+const (
+	commandServe      = "serve"
+	commandLogo       = "logo"
+	commandLogos      = "logos" // alias of "logo"
+	subcommandList    = "list"
+	subcommandConvert = "convert"
+)
+
+// Output formats (--output)
+const (
+	outputText       = "text"
+	outputJson       = "json"
+	outputPrometheus = "prometheus"
+)
+
 type cmdLineParams struct {
+	Command            string
+	Subcommand         string
+	Args               []string // positional arguments, Ex. the image of "logo convert"
+	Listen             string
+	Width              *int
+	Charset            *string
+	Watch              *time.Duration
 	Json               bool
+	Output             string
 	RefreshCache       bool
 	Cache              *bool
 	DisplayLogo        *bool
 	DisplayNerdSymbols *bool
 	Logo               *string
+	ImageLogo          string
+	Timeout            *time.Duration
+	Strict             bool
+	Format             *string
+	RecordDir          string
+	ReplayDir          string
 	Items              bool
 	Version            bool
 	ConfigFilePath     string
@@ -63,16 +135,27 @@ func parseCmdLineArgs(args []string) (*cmdLineParams, error) {
 
 	var (
 		jsonFlag           bool
+		outputFlag         string
+		listenFlag         string
+		imageLogoFlag      string
 		refreshCacheFlag   bool
 		itemsFlag          bool
 		versionFlag        bool
 		configFilePathFlag string
+		strictFlag         bool
+		formatFlag         string
+		recordDirFlag      string
+		replayDirFlag      string
 		helpFlag           bool
 	)
 	displayLogoFlag := new(bool)
 	displayNerdSymbolsFlag := new(bool)
 	cacheFlag := new(bool)
 	logoFlag := new(string)
+	widthFlag := new(int)
+	charsetFlag := new(string)
+	timeoutFlag := new(time.Duration)
+	watchFlag := new(time.Duration)
 
 	fs.BoolVar(&helpFlag, "help", false, "print this help message and exit.")
 	fs.BoolVar(&helpFlag, "h", false, "print this help message and exit.")
@@ -88,6 +171,9 @@ func parseCmdLineArgs(args []string) (*cmdLineParams, error) {
 	fs.BoolVar(&jsonFlag, "json", false, "display information in JSON instead of plain text (default: false).")
 	fs.BoolVar(&jsonFlag, "j", false, "display information in JSON instead of plain text (default: false).")
 
+	fs.StringVar(&outputFlag, "output", outputText, "output format: text, json or prometheus (default: text).")
+	fs.StringVar(&outputFlag, "o", outputText, "output format: text, json or prometheus (default: text).")
+
 	fs.BoolVar(cacheFlag, "cache", true, "use cache file (default: true).")
 	fs.BoolVar(cacheFlag, "c", true, "use cache file (default: true).")
 
@@ -100,12 +186,50 @@ func parseCmdLineArgs(args []string) (*cmdLineParams, error) {
 	fs.BoolVar(displayNerdSymbolsFlag, "nerd-symbols", true, "add nerd font symbol for each item title (default: true).")
 	fs.BoolVar(displayNerdSymbolsFlag, "n", true, "add nerd font symbol for each item title (default: true).")
 
-	fs.StringVar(logoFlag, "logo", "", "path to the logo file")
-	fs.StringVar(logoFlag, "l", "", "path to the logo file")
+	fs.StringVar(logoFlag, "logo", "", "name of a built-in logo, or path to a logo file.")
+	fs.StringVar(logoFlag, "l", "", "name of a built-in logo, or path to a logo file.")
+
+	fs.StringVar(&imageLogoFlag, "image-logo", "", "PNG or JPEG image displayed instead of the logo.")
+
+	fs.BoolVar(&strictFlag, "strict", false, "exit with an error if any item cannot be fetched (default: false).")
+
+	fs.StringVar(&formatFlag, "format", "", "Go text/template used to display the information.")
+
+	fs.DurationVar(watchFlag, "watch", 0, "display the information again on this interval.")
+	fs.DurationVar(watchFlag, "w", 0, "display the information again on this interval.")
 
-	err := fs.Parse(args)
-	if err != nil {
-		return nil, err
+	fs.StringVar(&listenFlag, "listen", "", "address the server listens on (serve).")
+
+	fs.IntVar(widthFlag, "width", defaultLogoWidth, "width of the converted logo (logo convert).")
+	fs.StringVar(charsetFlag, "charset", charsetBlocks, "characters of the converted logo (logo convert).")
+
+	fs.StringVar(&recordDirFlag, "record", "", "record the output of the commands into the directory.")
+	fs.StringVar(&replayDirFlag, "replay", "", "replay the output of the commands recorded into the directory.")
+
+	fs.DurationVar(timeoutFlag, "timeout", defaultTimeout, "overall time given to fetch the information.")
+	fs.DurationVar(timeoutFlag, "t", defaultTimeout, "overall time given to fetch the information.")
+
+	var command, subcommand string
+	if len(args) > 0 && args[0] == commandServe {
+		command, args = args[0], args[1:]
+	} else if len(args) > 0 && (args[0] == commandLogo || args[0] == commandLogos) {
+		if len(args) < 2 || (args[1] != subcommandList && args[1] != subcommandConvert) {
+			return nil, fmt.Errorf("usage: %s %s %s|%s", appName, commandLogo, subcommandList, subcommandConvert)
+		}
+		command, subcommand, args = commandLogo, args[1], args[2:]
+	}
+
+	// The flags can be before or after the positional arguments.
+	var positionalArgs []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positionalArgs = append(positionalArgs, fs.Arg(0))
+		args = fs.Args()[1:]
 	}
 	if helpFlag {
 		fs.Usage()
@@ -118,6 +242,11 @@ func parseCmdLineArgs(args []string) (*cmdLineParams, error) {
 	displayNerdSymbolsFlagSet := false
 	logoFlagSet := false
 	cacheFlagSet := false
+	timeoutFlagSet := false
+	formatFlagSet := false
+	watchFlagSet := false
+	widthFlagSet := false
+	charsetFlagSet := false
 	fs.Visit(func(f *flag.Flag) {
 		if f.Name == "display-logo" || f.Name == "d" {
 			displayLogoFlagSet = true
@@ -127,6 +256,16 @@ func parseCmdLineArgs(args []string) (*cmdLineParams, error) {
 			cacheFlagSet = true
 		} else if f.Name == "nerd-symbols" || f.Name == "n" {
 			displayNerdSymbolsFlagSet = true
+		} else if f.Name == "timeout" || f.Name == "t" {
+			timeoutFlagSet = true
+		} else if f.Name == "format" {
+			formatFlagSet = true
+		} else if f.Name == "watch" || f.Name == "w" {
+			watchFlagSet = true
+		} else if f.Name == "width" {
+			widthFlagSet = true
+		} else if f.Name == "charset" {
+			charsetFlagSet = true
 		}
 
 	})
@@ -142,14 +281,44 @@ func parseCmdLineArgs(args []string) (*cmdLineParams, error) {
 	if !displayNerdSymbolsFlagSet {
 		*displayNerdSymbolsFlag = true
 	}
+	if !timeoutFlagSet {
+		timeoutFlag = nil
+	}
+	if !watchFlagSet {
+		watchFlag = nil
+	}
+	if !widthFlagSet {
+		widthFlag = nil
+	}
+	if !charsetFlagSet {
+		charsetFlag = nil
+	}
+	var format *string
+	if formatFlagSet {
+		format = &formatFlag
+	}
 
 	return &cmdLineParams{
+		Command:            command,
+		Subcommand:         subcommand,
+		Args:               positionalArgs,
+		Listen:             listenFlag,
+		Width:              widthFlag,
+		Charset:            charsetFlag,
+		Watch:              watchFlag,
 		Json:               jsonFlag,
+		Output:             outputFlag,
 		RefreshCache:       refreshCacheFlag,
 		Cache:              cacheFlag,
 		DisplayLogo:        displayLogoFlag,
 		Logo:               logoFlag,
+		ImageLogo:          imageLogoFlag,
 		DisplayNerdSymbols: displayNerdSymbolsFlag,
+		Timeout:            timeoutFlag,
+		Strict:             strictFlag,
+		Format:             format,
+		RecordDir:          recordDirFlag,
+		ReplayDir:          replayDirFlag,
 		Items:              itemsFlag,
 		Version:            versionFlag,
 		ConfigFilePath:     configFilePathFlag,
@@ -164,15 +333,61 @@ func (cmdLine *cmdLineParams) controlCmdLineParams() {
 	if (cmdLine.Cache != nil && !*cmdLine.Cache) && cmdLine.RefreshCache {
 		log.Fatalf("--cache=false and --refresh=true are mutually exclusive")
 	}
+	if cmdLine.RecordDir != "" && cmdLine.ReplayDir != "" {
+		log.Fatalf("--record and --replay are mutually exclusive")
+	}
+	if (cmdLine.RecordDir != "" || cmdLine.ReplayDir != "") && cmdLine.RefreshCache {
+		log.Fatalf("--record/--replay and --refresh=true are mutually exclusive")
+	}
+	if cmdLine.Listen != "" && cmdLine.Command != commandServe {
+		log.Fatalf("--listen can only be used with %s", commandServe)
+	}
+	if cmdLine.Subcommand == subcommandConvert {
+		if len(cmdLine.Args) != 1 {
+			log.Fatalf("usage: %s %s %s <image>", appName, commandLogo, subcommandConvert)
+		}
+		if cmdLine.Width != nil && *cmdLine.Width <= 0 {
+			log.Fatalf("invalid width: %d", *cmdLine.Width)
+		}
+		if _, ok := charsetCellSizes[cmdLine.charset()]; !ok {
+			log.Fatalf("invalid charset: %s", cmdLine.charset())
+		}
+	} else if cmdLine.Width != nil || cmdLine.Charset != nil {
+		log.Fatalf("--width and --charset can only be used with %s %s", commandLogo, subcommandConvert)
+	}
+	if cmdLine.Watch != nil {
+		if *cmdLine.Watch <= 0 {
+			log.Fatalf("invalid watch interval: %s", *cmdLine.Watch)
+		}
+		if cmdLine.Command == commandServe {
+			log.Fatalf("--watch cannot be used with %s", commandServe)
+		}
+	}
+	if cmdLine.Timeout != nil && *cmdLine.Timeout <= 0 {
+		log.Fatalf("invalid timeout: %s", *cmdLine.Timeout)
+	}
+	switch cmdLine.Output {
+	case outputText, outputJson, outputPrometheus:
+	default:
+		log.Fatalf("invalid output format: %s", cmdLine.Output)
+	}
+	if cmdLine.Json {
+		if cmdLine.Output != outputText && cmdLine.Output != outputJson {
+			log.Fatalf("--json and --output %s are mutually exclusive", cmdLine.Output)
+		}
+		cmdLine.Output = outputJson
+	}
+	if cmdLine.Watch != nil && cmdLine.Output == outputPrometheus {
+		log.Fatalf("--watch cannot be used with --output %s", outputPrometheus)
+	}
+	if cmdLine.Format != nil {
+		if _, err := newTemplate("format", *cmdLine.Format); err != nil {
+			log.Fatalf("invalid format: %v", err)
+		}
+	}
 	if cmdLine.Items {
 		fmt.Println("Available information to choose from:")
-		var iArr []string
-
-		for k := range availableItems {
-			iArr = append(iArr, k)
-		}
-		sort.Strings(iArr)
-		for _, i := range iArr {
+		for _, i := range sysinfo.Items() {
 			fmt.Printf("  %s\n", i)
 		}
 		os.Exit(0)
@@ -192,3 +407,19 @@ func (cmdLine *cmdLineParams) controlCmdLineParams() {
 		}
 	}
 }
+
+// width returns the width of the converted logo (--width).
+func (cmdLine *cmdLineParams) width() int {
+	if cmdLine.Width == nil {
+		return defaultLogoWidth
+	}
+	return *cmdLine.Width
+}
+
+// charset returns the charset of the converted logo (--charset).
+func (cmdLine *cmdLineParams) charset() string {
+	if cmdLine.Charset == nil {
+		return charsetBlocks
+	}
+	return *cmdLine.Charset
+}
@@ -0,0 +1,90 @@
+package main
+
+/*
+This file contains the usage bars displayed in the values of some items
+(see BarsConfig), Ex. "[██████░░░░] 57%".
+
+The filled part of a bar is displayed with the warning or critical color of
+the theme (see theme.go) when the percentage reaches the thresholds of the bar.
+*/
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Bars which can be displayed
+const (
+	barDisk     = "disk"     // used space of the disk
+	barBattery  = "battery"  // charge of the battery
+	barCapacity = "capacity" // maximum capacity of the battery, compared to its design capacity
+	barVolumes  = "volumes"  // used space of each mounted volume
+	barMemory   = "memory"   // memory used by the applications and the system
+	barSwap     = "swap"     // used swap space
+)
+
+var barNames = []string{barDisk, barBattery, barCapacity, barVolumes, barMemory, barSwap}
+
+const defaultBarWidth = 10
+
+// Characters of the filled and the empty parts of the bars
+const defaultBarChars = "█░"
+
+// Default thresholds of the bars: a high disk usage, but a low battery charge or capacity is bad.
+var defaultBarThresholds = map[string]BarThresholds{
+	barDisk:     {Warning: 75, Critical: 90},
+	barBattery:  {Warning: 20, Critical: 10},
+	barCapacity: {Warning: 80, Critical: 60},
+	barVolumes:  {Warning: 75, Critical: 90},
+	barMemory:   {Warning: 80, Critical: 90},
+	barSwap:     {Warning: 50, Critical: 80},
+}
+
+// showBar returns true if the bar is to be displayed (see BarsConfig.Items).
+func showBar(name string) bool {
+	return config.Bars != nil && slices.Contains(config.Bars.Items, name)
+}
+
+// renderBar returns the bar of a percentage, followed by the percentage.
+func renderBar(name string, percent float64) string {
+	percent = math.Min(math.Max(percent, 0), 100)
+	chars := []rune(config.Bars.Chars)
+	filled := int(math.Round(percent * float64(config.Bars.Width) / 100))
+
+	var color string
+	switch config.Bars.Thresholds[name].level(percent) {
+	case levelWarning:
+		color = colorWarning
+	case levelCritical:
+		color = colorCritical
+	}
+	return fmt.Sprintf("[%s%s] %.0f%%",
+		colored(color, strings.Repeat(string(chars[0]), filled)),
+		colored(colorDim, strings.Repeat(string(chars[1]), config.Bars.Width-filled)),
+		percent,
+	)
+}
+
+// Levels of a value, compared to its thresholds
+const (
+	levelNormal = iota
+	levelWarning
+	levelCritical
+)
+
+// level returns the level of a percentage. When the warning threshold is higher than
+// the critical one, the low percentages are the bad ones (Ex. battery charge).
+// No thresholds (both 0) means the level is always normal.
+func (t BarThresholds) level(percent float64) int {
+	switch {
+	case t.Warning == 0 && t.Critical == 0:
+		return levelNormal
+	case t.Warning <= t.Critical && percent >= t.Critical, t.Warning > t.Critical && percent <= t.Critical:
+		return levelCritical
+	case t.Warning <= t.Critical && percent >= t.Warning, t.Warning > t.Critical && percent <= t.Warning:
+		return levelWarning
+	}
+	return levelNormal
+}
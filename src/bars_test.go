@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"minfo/pkg/sysinfo"
+)
+
+func TestBarThresholdsLevel(t *testing.T) {
+	tests := []struct {
+		thresholds BarThresholds
+		percent    float64
+		expected   int
+	}{
+		{BarThresholds{Warning: 75, Critical: 90}, 50, levelNormal},
+		{BarThresholds{Warning: 75, Critical: 90}, 75, levelWarning},
+		{BarThresholds{Warning: 75, Critical: 90}, 95, levelCritical},
+		// The low percentages are the bad ones.
+		{BarThresholds{Warning: 20, Critical: 10}, 50, levelNormal},
+		{BarThresholds{Warning: 20, Critical: 10}, 15, levelWarning},
+		{BarThresholds{Warning: 20, Critical: 10}, 5, levelCritical},
+		// No thresholds
+		{BarThresholds{}, 100, levelNormal},
+	}
+	for _, test := range tests {
+		if actual := test.thresholds.level(test.percent); actual != test.expected {
+			t.Errorf("%+v.level(%.0f) = %d, expected %d", test.thresholds, test.percent, actual, test.expected)
+		}
+	}
+}
+
+func TestRenderBar(t *testing.T) {
+	config = &Config{Bars: &BarsConfig{
+		Width:      10,
+		Chars:      "#-",
+		Thresholds: map[string]BarThresholds{barDisk: {Warning: 75, Critical: 90}},
+	}}
+	savedDim, savedCritical := colorDim, colorCritical
+	colorDim, colorCritical = "", "<critical>"
+	defer func() { colorDim, colorCritical = savedDim, savedCritical }()
+
+	tests := []struct {
+		percent  float64
+		expected string
+	}{
+		{57, "[######----] 57%"},
+		{0, "[----------] 0%"},
+		{120, "[<critical>##########\u001B[0m] 100%"},
+	}
+	for _, test := range tests {
+		if actual := renderBar(barDisk, test.percent); actual != test.expected {
+			t.Errorf("renderBar(%.0f) = %q, expected %q", test.percent, actual, test.expected)
+		}
+	}
+}
+
+func TestMemoryUsageBars(t *testing.T) {
+	config = &Config{Bars: &BarsConfig{
+		Items:      []string{barMemory, barSwap},
+		Width:      10,
+		Chars:      "#-",
+		Thresholds: map[string]BarThresholds{},
+	}}
+	savedDim := colorDim
+	colorDim = ""
+	defer func() { colorDim = savedDim }()
+
+	hostInfo := &sysinfo.Info{MemoryUsage: &sysinfo.MemoryUsage{
+		TotalBytes:     16 << 30,
+		UsedBytes:      8 << 30,
+		UsedPercent:    50,
+		SwapTotalBytes: 4 << 30,
+		SwapUsedBytes:  1 << 30,
+	}}
+	lines := memoryUsageItem.lines(memoryUsageItem, hostInfo)
+	expected := map[string]string{
+		"Memory usage": "[#####-----] 50%",
+		"Swap":         "[###-------] 25%",
+	}
+	for _, line := range lines {
+		if bar, ok := expected[line.Title]; ok {
+			if !strings.HasSuffix(line.Value, bar) {
+				t.Errorf("%s: expected %q to end with %q", line.Title, line.Value, bar)
+			}
+			delete(expected, line.Title)
+		}
+	}
+	if len(expected) > 0 {
+		t.Errorf("Missing lines: %v", expected)
+	}
+}
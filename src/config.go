@@ -2,23 +2,42 @@ package main
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
+
+	"minfo/pkg/sysinfo"
 
-	"github.com/jwalton/go-supportscolor"
 	"gopkg.in/yaml.v3"
 )
 
 // This struct represents the configuration file
 type Config struct {
-	CacheFilePath      *string        `yaml:"cache_file,omitempty"`
-	DisplayLogo        *bool          `yaml:"display_logo,omitempty"`
-	Logo               *string        `yaml:"logo_file,omitempty"`
-	Cache              *bool          `yaml:"cache,omitempty"`
-	DisplayNerdSymbols *bool          `yaml:"nerd_symbols,omitempty"`
-	Items              []string       `yaml:"items,omitempty"`
-	Weather            *WeatherConfig `yaml:"weather,omitempty"`
+	CacheFilePath      *string                    `yaml:"cache_file,omitempty"`
+	DisplayLogo        *bool                      `yaml:"display_logo,omitempty"`
+	Logo               *string                    `yaml:"logo,omitempty"`      // "auto", name of a built-in logo, or path of a logo file
+	LogoFile           *string                    `yaml:"logo_file,omitempty"` // deprecated, same as logo
+	Cache              *bool                      `yaml:"cache,omitempty"`
+	DisplayNerdSymbols *bool                      `yaml:"nerd_symbols,omitempty"`
+	Items              []string                   `yaml:"items,omitempty"`
+	Weather            *WeatherConfig             `yaml:"weather,omitempty"`
+	Timeout            *time.Duration             `yaml:"timeout,omitempty"`
+	ItemTimeouts       map[string]time.Duration   `yaml:"item_timeouts,omitempty"`
+	Format             *string                    `yaml:"format,omitempty"`
+	ItemFormats        map[string]string          `yaml:"item_formats,omitempty"`
+	Serve              *ServeConfig               `yaml:"serve,omitempty"`
+	ImageLogo          *ImageLogoConfig           `yaml:"image_logo,omitempty"`
+	Overflow           string                     `yaml:"overflow,omitempty"` // values too long for the terminal: truncate or wrap
+	Layout             *LayoutConfig              `yaml:"layout,omitempty"`
+	Theme              *ThemeConfig               `yaml:"theme,omitempty"`
+	Bars               *BarsConfig                `yaml:"bars,omitempty"`
+	Thresholds         map[string]ThresholdConfig `yaml:"thresholds,omitempty"`
+	Volumes            *VolumesConfig             `yaml:"volumes,omitempty"`
 }
 
 type WeatherConfig struct {
@@ -31,209 +50,110 @@ type WeatherConfig struct {
 	Lang              string   `yaml:"lang,omitempty"`
 }
 
-var config = &Config{}
+// Configuration of the HTTP server mode ("minfo serve")
+type ServeConfig struct {
+	Listen        string                   `yaml:"listen,omitempty"`
+	Interval      *time.Duration           `yaml:"interval,omitempty"`
+	ItemIntervals map[string]time.Duration `yaml:"item_intervals,omitempty"`
+}
 
-/* ---------- Default Configuration ---------- */
-var defaultCacheFilePath = fmt.Sprintf("%s/.cache/minfo/static.json", envHome)
-var defaultItems = []string{
-	"user",
-	"hostname",
-	"os",
-	"system_integrity",
-	"serial_number",
-	"model",
-	"cpu",
-	"gpu",
-	"memory",
-	"disk",
-	"battery",
-	"display",
-	"terminal",
-	"software",
-	"public_ip",
-	"uptime",
-	"datetime",
+// Image displayed instead of the text logo, if the terminal supports it (see logo_image.go)
+type ImageLogoConfig struct {
+	Path     string `yaml:"path"`
+	Width    int    `yaml:"width,omitempty"`    // in cells
+	Protocol string `yaml:"protocol,omitempty"` // auto, kitty, iterm2 or sixel
 }
 
-/* ---------- AVAILABLE ITEMS ---------- */
-// For things to be retrieved from system_profiler,
-// We need to know the SPDataType to fetch.
-var (
-	SPSoftwareDataType = "SPSoftwareDataType"
-	SPHardwareDataType = "SPHardwareDataType"
-	SPMemoryDataType   = "SPMemoryDataType"
-	SPDisplaysDataType = "SPDisplaysDataType"
-	SPPowerDataType    = "SPPowerDataType"
-	SPStorageDataType  = "SPStorageDataType"
-)
+// Layout of the text output (see layout.go)
+type LayoutConfig struct {
+	LogoPosition string `yaml:"logo_position,omitempty"` // left, right or top
+	Separator    string `yaml:"separator,omitempty"`     // between the title and the value, Ex. ": "
+	TitleAlign   string `yaml:"title_align,omitempty"`   // left or right
+	Header       bool   `yaml:"header,omitempty"`        // user@hostname, underlined
+	Sections     bool   `yaml:"sections,omitempty"`      // items grouped by section, with section titles
+}
 
-// For things that are not retrieved from system_profiler,
-// we need to know which function to call to get the information.
-// We use a NamedFunc struct because we cannot have pointers
-// to functions, and the "item" struct must have pointers,
-// because its "Func" field is optional (so we need to be able
-// to set it to nil).
-type NamedFunc struct {
-	Id   string
-	Func func(*info)
+// Colors of the text output (see theme.go): "#RRGGBB", 256 colors index or ANSI color name
+type ThemeConfig struct {
+	Name      string `yaml:"name,omitempty"` // built-in theme, whose colors are overridden by the following ones
+	Title     string `yaml:"title,omitempty"`
+	Value     string `yaml:"value,omitempty"`
+	Separator string `yaml:"separator,omitempty"`
+	Warning   string `yaml:"warning,omitempty"`
+	Critical  string `yaml:"critical,omitempty"`
+	Logo      string `yaml:"logo,omitempty"` // color of the logos without colors
 }
 
-var (
-	datetimeNamedFunc = NamedFunc{
-		Id:   "fetchDateTime",
-		Func: fetchDateTime,
-	}
-	publicIpNamedFunc = NamedFunc{
-		Id:   "fetchPublicIp",
-		Func: fetchPublicIp,
-	}
-	softwareNamedFunc = NamedFunc{
-		Id:   "fetchSoftware",
-		Func: fetchSoftware,
-	}
-	termProgramNamedFunc = NamedFunc{
-		Id:   "fetchTermProgram",
-		Func: fetchTermProgram,
-	}
-	weatherNamedFunc = NamedFunc{
-		Id:   "fetchWeatherOpenMeteo",
-		Func: fetchWeatherOpenMeteo,
+// UnmarshalYAML accepts the name of a built-in theme ("theme: nord")
+// as well as a theme section.
+func (t *ThemeConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		t.Name = value.Value
+		return nil
 	}
-)
+	type plainThemeConfig ThemeConfig
+	return value.Decode((*plainThemeConfig)(t))
+}
 
-// Defines an item that can be fetched and displayed.
-// Title: The title of the item to display. Ex. "Public IP"
-//   - Required
-//
-// SPDataType: The SPDataType to fetch from system_profiler. Ex. "SPSoftwareDataType"
-//   - Optional (i.e. default = nil)
-//
-// Func: The function to call to get the information.
-//   - Optional (i.e. default = nil)
-//
-// IsCached: Whether the information should be cached or not.
-//   - Optional (i.e. default = false)
-type item struct {
-	Title      string
-	Nerd       string // Nerd Font symbol
-	SPDataType *string
-	Func       *NamedFunc
-	IsCached   bool
+// Usage bars displayed in the values of the items (see bars.go)
+type BarsConfig struct {
+	Items      []string                 `yaml:"items,omitempty"` // disk, battery, capacity, volumes, memory
+	Width      int                      `yaml:"width,omitempty"` // in characters
+	Chars      string                   `yaml:"chars,omitempty"` // characters of the filled and empty parts, Ex. "█░"
+	Thresholds map[string]BarThresholds `yaml:"thresholds,omitempty"`
 }
 
-// All available items we can fetch and display.
-var availableItems = map[string]item{
-	/* ---------- System Profiler Data (cached data) ---------- */
-	"cpu": {
-		Title:      "CPU",
-		Nerd:       "",
-		SPDataType: &SPHardwareDataType,
-		IsCached:   true,
-	},
-	"gpu": {
-		Title:      "GPU",
-		Nerd:       "",
-		SPDataType: &SPDisplaysDataType,
-		IsCached:   true,
-	},
-	"model": {
-		Title:      "Model",
-		Nerd:       "",
-		SPDataType: &SPHardwareDataType,
-		IsCached:   true,
-	},
-	"memory": {
-		Title:      "Memory",
-		Nerd:       "",
-		SPDataType: &SPMemoryDataType,
-		IsCached:   true,
-	},
-	"serial_number": {
-		Title:      "Serial",
-		Nerd:       "",
-		SPDataType: &SPHardwareDataType,
-		IsCached:   true,
-	},
-	/* ---------- System Profiler Data (non-cached data) ---------- */
-	"battery": {
-		Title:      "Battery",
-		Nerd:       "󰂄",
-		SPDataType: &SPPowerDataType,
-	},
-	"disk": {
-		Title:      "Disk",
-		Nerd:       "󰋊",
-		SPDataType: &SPStorageDataType,
-	},
-	"display": {
-		Title:      "Display",
-		Nerd:       "",
-		SPDataType: &SPDisplaysDataType,
-	},
-	"hostname": {
-		Title:      "Hostname",
-		Nerd:       "",
-		SPDataType: &SPSoftwareDataType,
-	},
-	"os": {
-		Title:      "OS",
-		Nerd:       "",
-		SPDataType: &SPSoftwareDataType,
-	},
-	"system_integrity": {
-		Title:      "macOS SIP",
-		Nerd:       "",
-		SPDataType: &SPSoftwareDataType,
-	},
-	"uptime": {
-		Title:      "Uptime",
-		Nerd:       "",
-		SPDataType: &SPSoftwareDataType,
-	},
-	"user": {
-		Title:      "User",
-		Nerd:       "",
-		SPDataType: &SPSoftwareDataType,
-	},
-	/* ---------- Other Data ---------- */
-	"datetime": {
-		Title: "Date/Time",
-		Nerd:  "",
-		Func:  &datetimeNamedFunc,
-	},
-	"public_ip": {
-		Title: "Public IP",
-		Nerd:  "󱦂",
-		Func:  &publicIpNamedFunc,
-	},
-	"software": {
-		Title: "Software",
-		Nerd:  "",
-		Func:  &softwareNamedFunc,
-	},
-	"terminal": {
-		Title: "Terminal",
-		Nerd:  "",
-		Func:  &termProgramNamedFunc,
-	},
-	"weather": {
-		Title: "Weather",
-		Nerd:  "󰖙",
-		Func:  &weatherNamedFunc,
-	},
+// Percentages from which a bar is displayed with the warning or critical color.
+// When Warning is higher than Critical, the low percentages are the bad ones.
+type BarThresholds struct {
+	Warning  float64 `yaml:"warning"`
+	Critical float64 `yaml:"critical"`
 }
 
-func getDefaultLogoFilePath() (defaultLogoFilePath *string) {
-	defaultLogoFilePath = new(string)
-	*defaultLogoFilePath = os.Getenv("HOMEBREW_PREFIX")
-	if *defaultLogoFilePath != "" {
-		if supportscolor.Stdout().Has256 || supportscolor.Stderr().Has16m {
-			*defaultLogoFilePath = fmt.Sprintf("%s/share/minfo/apple-256colors", *defaultLogoFilePath)
-		} else {
-			*defaultLogoFilePath = fmt.Sprintf("%s/share/minfo/apple-16colors", *defaultLogoFilePath)
+// Warning and critical conditions of a checked value (see thresholds.go), Ex. "< 20"
+type ThresholdConfig struct {
+	Warning  string `yaml:"warning,omitempty"`
+	Critical string `yaml:"critical,omitempty"`
+}
+
+// Volumes listed by the volumes item: mount points globs (Ex. "/Volumes/*")
+// and file systems (Ex. "apfs", "nfs")
+type VolumesConfig struct {
+	IncludeMountPoints []string `yaml:"include_mount_points,omitempty"`
+	ExcludeMountPoints []string `yaml:"exclude_mount_points,omitempty"`
+	IncludeFileSystems []string `yaml:"include_file_systems,omitempty"`
+	ExcludeFileSystems []string `yaml:"exclude_file_systems,omitempty"`
+}
+
+var config = &Config{}
+
+/* ---------- Default Configuration ---------- */
+var defaultCacheFilePath = fmt.Sprintf("%s/.cache/minfo/static.json", envHome)
+var defaultTimeout = sysinfo.DefaultTimeout
+var defaultItems = sysinfo.DefaultItems
+var defaultLogo = logoAuto
+var defaultSeparator = " "
+
+// supportedItems removes from the requested items those that cannot be
+// fetched on the current operating system, so that the same configuration
+// file can be used on every platform.
+func supportedItems(items []string) []string {
+	var supported []string
+	for _, item := range items {
+		if sysinfo.Supported(item, goos) {
+			supported = append(supported, item)
 		}
 	}
-	return
+	return supported
+}
+
+// itemInterval returns the interval between two collections of an item:
+// its own interval if defined in the configuration file, the default interval otherwise.
+func (sc *ServeConfig) itemInterval(name string) time.Duration {
+	if interval, ok := sc.ItemIntervals[name]; ok {
+		return interval
+	}
+	return *sc.Interval
 }
 
 // Load the configuration file and check if the requested items are valid
@@ -243,14 +163,26 @@ func loadAndCheckConfig(configFilePath string) (err error) {
 		config = &Config{
 			CacheFilePath:      &defaultCacheFilePath,
 			DisplayLogo:        nil,
-			Logo:               getDefaultLogoFilePath(),
+			Logo:               &defaultLogo,
 			Cache:              nil,
 			DisplayNerdSymbols: nil,
 			Items:              defaultItems,
+			Timeout:            &defaultTimeout,
+			Serve: &ServeConfig{
+				Listen:   defaultListen,
+				Interval: &defaultServeInterval,
+			},
 			Weather: &WeatherConfig{
 				Units: "metric",
 				Lang:  "en",
 			},
+			Overflow: overflowTruncate,
+			Layout: &LayoutConfig{
+				LogoPosition: positionLeft,
+				Separator:    defaultSeparator,
+				TitleAlign:   alignLeft,
+			},
+			Thresholds: maps.Clone(defaultThresholds),
 		}
 		return nil
 	}
@@ -269,7 +201,7 @@ func loadAndCheckConfig(configFilePath string) (err error) {
 	if config.Items != nil {
 		// Check if all requested items are valid
 		for _, item := range config.Items {
-			if _, exists := availableItems[item]; !exists {
+			if !slices.Contains(sysinfo.Items(), item) {
 				return fmt.Errorf("invalid item: %s", item)
 			}
 		}
@@ -324,6 +256,13 @@ func loadAndCheckConfig(configFilePath string) (err error) {
 	} else {
 		config.CacheFilePath = &defaultCacheFilePath
 	}
+	if config.Logo != nil && (*config.Logo == "true" || *config.Logo == "false") {
+		// "logo: true" of the sample configuration of previous versions (see display_logo)
+		config.Logo = nil
+	}
+	if config.Logo == nil {
+		config.Logo = config.LogoFile
+	}
 	if config.Logo != nil {
 		// Replace '~' with the home directory
 		if strings.HasPrefix(*config.Logo, "~") {
@@ -334,12 +273,189 @@ func loadAndCheckConfig(configFilePath string) (err error) {
 			*config.Logo = filepath.Join(homeDir, (*config.Logo)[1:])
 		}
 	} else {
-		config.Logo = getDefaultLogoFilePath()
+		config.Logo = &defaultLogo
+	}
+	if config.ImageLogo != nil {
+		if config.ImageLogo.Path == "" {
+			return fmt.Errorf("image_logo: path is required")
+		}
+		if strings.HasPrefix(config.ImageLogo.Path, "~") {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("error getting home directory: %w", err)
+			}
+			config.ImageLogo.Path = filepath.Join(homeDir, config.ImageLogo.Path[1:])
+		}
+		if config.ImageLogo.Width < 0 {
+			return fmt.Errorf("invalid image_logo width: %d", config.ImageLogo.Width)
+		}
+		switch config.ImageLogo.Protocol {
+		case "", protocolAuto, protocolKitty, protocolITerm2, protocolSixel:
+		default:
+			return fmt.Errorf("invalid image_logo protocol: %s", config.ImageLogo.Protocol)
+		}
+	}
+	switch config.Overflow {
+	case "":
+		config.Overflow = overflowTruncate
+	case overflowTruncate, overflowWrap:
+	default:
+		return fmt.Errorf("invalid overflow: %s", config.Overflow)
+	}
+	if config.Layout == nil {
+		config.Layout = &LayoutConfig{}
+	}
+	switch config.Layout.LogoPosition {
+	case "":
+		config.Layout.LogoPosition = positionLeft
+	case positionLeft, positionRight, positionTop:
+	default:
+		return fmt.Errorf("invalid layout logo_position: %s", config.Layout.LogoPosition)
+	}
+	if config.Layout.Separator == "" {
+		config.Layout.Separator = defaultSeparator
+	}
+	switch config.Layout.TitleAlign {
+	case "":
+		config.Layout.TitleAlign = alignLeft
+	case alignLeft, alignRight:
+	default:
+		return fmt.Errorf("invalid layout title_align: %s", config.Layout.TitleAlign)
+	}
+	if _, err := resolveTheme(config.Theme); err != nil {
+		return fmt.Errorf("invalid theme: %w", err)
+	}
+	if config.Bars != nil {
+		for _, bar := range config.Bars.Items {
+			if !slices.Contains(barNames, bar) {
+				return fmt.Errorf("invalid bar: %s", bar)
+			}
+		}
+		if config.Bars.Width == 0 {
+			config.Bars.Width = defaultBarWidth
+		} else if config.Bars.Width < 0 {
+			return fmt.Errorf("invalid bars width: %d", config.Bars.Width)
+		}
+		if config.Bars.Chars == "" {
+			config.Bars.Chars = defaultBarChars
+		} else if utf8.RuneCountInString(config.Bars.Chars) != 2 {
+			return fmt.Errorf("invalid bars chars: %s (2 characters expected: filled and empty)", config.Bars.Chars)
+		}
+		for bar := range config.Bars.Thresholds {
+			if !slices.Contains(barNames, bar) {
+				return fmt.Errorf("invalid bar in thresholds: %s", bar)
+			}
+		}
+		if config.Bars.Thresholds == nil {
+			config.Bars.Thresholds = map[string]BarThresholds{}
+		}
+		for bar, thresholds := range defaultBarThresholds {
+			if _, ok := config.Bars.Thresholds[bar]; !ok {
+				config.Bars.Thresholds[bar] = thresholds
+			}
+		}
+	}
+	for name, thresholds := range config.Thresholds {
+		if _, ok := checks[name]; !ok {
+			return fmt.Errorf("invalid check in thresholds: %s", name)
+		}
+		if err := thresholds.validate(); err != nil {
+			return fmt.Errorf("invalid thresholds of %s: %w", name, err)
+		}
+	}
+	if config.Thresholds == nil {
+		config.Thresholds = map[string]ThresholdConfig{}
+	}
+	for name, thresholds := range defaultThresholds {
+		if _, ok := config.Thresholds[name]; !ok {
+			config.Thresholds[name] = thresholds
+		}
+	}
+	if config.Volumes != nil {
+		for _, pattern := range slices.Concat(config.Volumes.IncludeMountPoints, config.Volumes.ExcludeMountPoints) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid volumes mount point: %s", pattern)
+			}
+		}
 	}
 	if config.DisplayNerdSymbols == nil {
 		config.DisplayNerdSymbols = new(bool)
 		*config.DisplayNerdSymbols = true // This default value might be overridden by the command line
 	}
+	if config.Timeout == nil {
+		config.Timeout = &defaultTimeout // This default value might be overridden by the command line
+	} else if *config.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout: %s", *config.Timeout)
+	}
+	for item, timeout := range config.ItemTimeouts {
+		if !slices.Contains(sysinfo.Items(), item) {
+			return fmt.Errorf("invalid item in item_timeouts: %s", item)
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("invalid timeout for %s: %s", item, timeout)
+		}
+	}
+	if config.Serve == nil {
+		config.Serve = &ServeConfig{}
+	}
+	if config.Serve.Listen == "" {
+		config.Serve.Listen = defaultListen
+	}
+	if config.Serve.Interval == nil {
+		config.Serve.Interval = &defaultServeInterval
+	} else if *config.Serve.Interval <= 0 {
+		return fmt.Errorf("invalid serve interval: %s", *config.Serve.Interval)
+	}
+	for item, interval := range config.Serve.ItemIntervals {
+		if !slices.Contains(sysinfo.Items(), item) {
+			return fmt.Errorf("invalid item in serve item_intervals: %s", item)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("invalid serve interval for %s: %s", item, interval)
+		}
+	}
+	if config.Format != nil {
+		if _, err := newTemplate("format", *config.Format); err != nil {
+			return fmt.Errorf("invalid format: %w", err)
+		}
+	}
+	for item := range config.ItemFormats {
+		if !slices.Contains(sysinfo.Items(), item) {
+			return fmt.Errorf("invalid item in item_formats: %s", item)
+		}
+	}
+	if _, err := parseItemFormats(config.ItemFormats); err != nil {
+		return fmt.Errorf("invalid item format: %w", err)
+	}
 
 	return nil
 }
+
+// weatherOptions converts the weather configuration to the options of sysinfo.Collect.
+func (wc *WeatherConfig) weatherOptions() sysinfo.WeatherOptions {
+	opts := sysinfo.WeatherOptions{
+		Latitude:  wc.Latitude,
+		Longitude: wc.Longitude,
+		Units:     wc.Units,
+		Lang:      wc.Lang,
+	}
+	if wc.LocationNameEn != nil {
+		opts.LocationName = *wc.LocationNameEn
+		opts.LocationCountry = *wc.LocationCountryEn
+		opts.LocationState = *wc.LocationStateEn
+	}
+	return opts
+}
+
+// volumeFilter converts the volumes configuration to the options of sysinfo.Collect.
+func (vc *VolumesConfig) volumeFilter() sysinfo.VolumeFilter {
+	if vc == nil {
+		return sysinfo.VolumeFilter{}
+	}
+	return sysinfo.VolumeFilter{
+		IncludeMountPoints: vc.IncludeMountPoints,
+		ExcludeMountPoints: vc.ExcludeMountPoints,
+		IncludeFileSystems: vc.IncludeFileSystems,
+		ExcludeFileSystems: vc.ExcludeFileSystems,
+	}
+}
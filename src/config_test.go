@@ -88,3 +88,114 @@ func TestLoadConfig_EmptyConfig(t *testing.T) {
 	}
 
 }
+
+// Test the logo (name or path), and the deprecated logo_file
+func TestLoadConfig_Logo(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"items: [cpu]\n", defaultLogo},
+		{"logo: builtin:apple-nocolor\n", "builtin:apple-nocolor"},
+		{"logo_file: /tmp/logo.yaml\n", "/tmp/logo.yaml"},
+		{"logo: true\n", defaultLogo},
+	}
+	for _, test := range tests {
+		filePath, err := createTempConfigFile(test.content)
+		if err != nil {
+			t.Fatalf("Failed to create temp file: %v", err)
+		}
+		defer os.Remove(filePath) // Clean up
+
+		config = &Config{}
+		if err := loadAndCheckConfig(filePath); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if *config.Logo != test.expected {
+			t.Errorf("Config %q: expected logo '%s', got '%s'", test.content, test.expected, *config.Logo)
+		}
+	}
+}
+
+func TestLoadConfig_ImageLogo(t *testing.T) {
+	tests := []struct {
+		content string
+		valid   bool
+	}{
+		{"image_logo:\n  path: /tmp/logo.png\n  width: 20\n  protocol: sixel\n", true},
+		{"image_logo:\n  width: 20\n", false},
+		{"image_logo:\n  path: /tmp/logo.png\n  protocol: vt340\n", false},
+	}
+	for _, test := range tests {
+		filePath, err := createTempConfigFile(test.content)
+		if err != nil {
+			t.Fatalf("Failed to create temp file: %v", err)
+		}
+		defer os.Remove(filePath) // Clean up
+
+		config = &Config{}
+		err = loadAndCheckConfig(filePath)
+		if test.valid && err != nil {
+			t.Errorf("Config %q: unexpected error: %v", test.content, err)
+		} else if !test.valid && err == nil {
+			t.Errorf("Config %q: expected an error", test.content)
+		}
+	}
+}
+
+func TestLoadConfig_Theme(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected ThemeConfig
+	}{
+		{"theme: dracula\n", ThemeConfig{Name: "dracula"}},
+		{"theme:\n  name: nord\n  title: \"#FFFFFF\"\n", ThemeConfig{Name: "nord", Title: "#FFFFFF"}},
+	}
+	for _, test := range tests {
+		filePath, err := createTempConfigFile(test.content)
+		if err != nil {
+			t.Fatalf("Failed to create temp file: %v", err)
+		}
+		defer os.Remove(filePath) // Clean up
+
+		config = &Config{}
+		if err := loadAndCheckConfig(filePath); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if *config.Theme != test.expected {
+			t.Errorf("Config %q: expected theme %+v, got %+v", test.content, test.expected, *config.Theme)
+		}
+	}
+}
+
+func TestLoadConfig_Thresholds(t *testing.T) {
+	filePath, err := createTempConfigFile("thresholds:\n  disk_free: {warning: \"< 30\"}\n  battery_health: {}\n")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(filePath) // Clean up
+
+	config = &Config{}
+	if err := loadAndCheckConfig(filePath); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Overridden, disabled, and default thresholds
+	if config.Thresholds["disk_free"] != (ThresholdConfig{Warning: "< 30"}) ||
+		config.Thresholds["battery_health"] != (ThresholdConfig{}) ||
+		config.Thresholds["smart_status"] != defaultThresholds["smart_status"] {
+		t.Errorf("Unexpected thresholds: %v", config.Thresholds)
+	}
+
+	for _, content := range []string{"thresholds:\n  cpu: {warning: \"> 90\"}\n", "thresholds:\n  disk_free: {warning: \"20\"}\n"} {
+		filePath, err := createTempConfigFile(content)
+		if err != nil {
+			t.Fatalf("Failed to create temp file: %v", err)
+		}
+		defer os.Remove(filePath) // Clean up
+
+		config = &Config{}
+		if err := loadAndCheckConfig(filePath); err == nil {
+			t.Errorf("Config %q: expected an error", content)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+/*
+This file contains the custom output formats: Go text/template evaluated
+against the fetched information (sysinfo.Info), either for the whole output
+(--format / "format:") or for a single item ("item_formats:").
+*/
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"minfo/pkg/sysinfo"
+)
+
+// Colors that can be used with the "color" template function.
+var templateColors = map[string]string{
+	"black":   "\u001B[30m",
+	"red":     "\u001B[31m",
+	"green":   "\u001B[32m",
+	"yellow":  "\u001B[33m",
+	"blue":    "\u001B[34m",
+	"magenta": "\u001B[35m",
+	"cyan":    "\u001B[36m",
+	"white":   "\u001B[37m",
+	"bold":    "\u001B[1m",
+	"dim":     colorDim,
+	"normal":  colorNormal,
+}
+
+// Helper functions available in the templates.
+var templateFuncs = template.FuncMap{
+	// {{bytes 1500000000}} --> "1.5 GB"
+	"bytes": formatBytes,
+	// {{percent .Disk.FreeTB .Disk.TotalTB}} --> "57%"
+	"percent": formatPercent,
+	// {{color "red" "text"}} --> "text" in red
+	"color": func(name string, text any) (string, error) {
+		code, ok := templateColors[name]
+		if !ok {
+			return "", fmt.Errorf("unknown color: %s", name)
+		}
+		return fmt.Sprintf("%s%v%s", code, text, colorNormal), nil
+	},
+	// {{nerd "cpu"}} --> Nerd Font symbol of the item (empty if nerd symbols are disabled)
+	"nerd": func(name string) (string, error) {
+		it, ok := items[name]
+		if !ok {
+			return "", fmt.Errorf("unknown item: %s", name)
+		}
+		if config.DisplayNerdSymbols != nil && !*config.DisplayNerdSymbols {
+			return "", nil
+		}
+		return it.nerd, nil
+	},
+}
+
+// newTemplate parses a template, with the helper functions.
+func newTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Parse(text)
+}
+
+// executeTemplate evaluates the template against the fetched information,
+// and returns the resulting lines.
+func executeTemplate(tmpl *template.Template, hostInfo *sysinfo.Info) ([]string, error) {
+	var output strings.Builder
+	if err := tmpl.Execute(&output, hostInfo); err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimRight(output.String(), "\n"), "\n"), nil
+}
+
+// parseItemFormats parses the templates of the items (see Config.ItemFormats).
+func parseItemFormats(itemFormats map[string]string) (map[string]*template.Template, error) {
+	templates := map[string]*template.Template{}
+	for name, text := range itemFormats {
+		tmpl, err := newTemplate(name, text)
+		if err != nil {
+			return nil, err
+		}
+		templates[name] = tmpl
+	}
+	return templates, nil
+}
+
+// toFloat converts any number to a float64.
+func toFloat(v any) (float64, error) {
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	case reflect.Pointer:
+		if !value.IsNil() {
+			return toFloat(value.Elem().Interface())
+		}
+	}
+	return 0, fmt.Errorf("not a number: %v", v)
+}
+
+// formatBytes formats a number of bytes with the appropriate unit
+// (1 kB = 1000 bytes, like macOS does).
+func formatBytes(v any) (string, error) {
+	n, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+	units := []string{"B", "kB", "MB", "GB", "TB", "PB"}
+	i := 0
+	for math.Abs(n) >= 1000 && i < len(units)-1 {
+		n /= 1000
+		i++
+	}
+	return fmt.Sprintf("%s %s", formatFloat(n), units[i]), nil
+}
+
+// formatPercent formats part/total as a rounded percentage.
+func formatPercent(part, total any) (string, error) {
+	p, err := toFloat(part)
+	if err != nil {
+		return "", err
+	}
+	t, err := toFloat(total)
+	if err != nil {
+		return "", err
+	}
+	if t == 0 {
+		return "0%", nil
+	}
+	return fmt.Sprintf("%.0f%%", p*100/t), nil
+}
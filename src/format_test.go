@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"minfo/pkg/sysinfo"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{512, "512 B"},
+		{1500000000, "1.5 GB"},
+		{uint64(2000000000000), "2 TB"},
+		{float32(1234.5), "1.2 kB"},
+	}
+	for _, test := range tests {
+		if actual, err := formatBytes(test.value); err != nil || actual != test.expected {
+			t.Errorf("formatBytes(%v) = '%s' (%v), expected '%s'", test.value, actual, err, test.expected)
+		}
+	}
+	if _, err := formatBytes("foo"); err == nil {
+		t.Errorf("Expected an error for a non-number")
+	}
+}
+
+func TestFormatPercent(t *testing.T) {
+	if actual, _ := formatPercent(float32(1.14), float32(2)); actual != "57%" {
+		t.Errorf("Expected '57%%', got '%s'", actual)
+	}
+	if actual, _ := formatPercent(1, 0); actual != "0%" {
+		t.Errorf("Expected '0%%', got '%s'", actual)
+	}
+}
+
+func TestExecuteTemplate(t *testing.T) {
+	tmpl, err := newTemplate("test", `{{.Hostname}} {{.Cpu.Model}}
+{{color "red" "down"}}`)
+	if err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+	hostInfo := &sysinfo.Info{
+		CachedInfo: sysinfo.CachedInfo{Cpu: &sysinfo.Cpu{Model: "Apple M4"}},
+		Hostname:   "host",
+	}
+	lines, err := executeTemplate(tmpl, hostInfo)
+	if err != nil {
+		t.Fatalf("Failed to execute template: %v", err)
+	}
+	expected := []string{"host Apple M4", "\u001B[31mdown" + colorNormal}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %q", len(expected), lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Line %d: expected %q, got %q", i, expected[i], lines[i])
+		}
+	}
+}
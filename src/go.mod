@@ -6,10 +6,7 @@ require github.com/micromdm/plist v0.2.1
 
 require (
 	github.com/jwalton/go-supportscolor v1.2.0
+	golang.org/x/sys v0.0.0-20210220050731-9a76102bfb43
+	golang.org/x/term v0.0.0-20210220032956-6a3ed077a48d
 	gopkg.in/yaml.v3 v3.0.1
 )
-
-require (
-	golang.org/x/sys v0.0.0-20210220050731-9a76102bfb43 // indirect
-	golang.org/x/term v0.0.0-20210220032956-6a3ed077a48d // indirect
-)
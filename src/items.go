@@ -0,0 +1,584 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"minfo/pkg/sysinfo"
+)
+
+/*
+This file contains how each item fetched by package sysinfo is displayed in plain text.
+To add a new item, define its collector in package sysinfo, and its display here.
+*/
+
+// item describes how an item (see sysinfo.Items) is displayed in plain text.
+type item struct {
+	name  string
+	title string
+	// Nerd Font symbol displayed in front of the title.
+	nerd string
+	// Section of the item, when the items are grouped (see LayoutConfig.Sections).
+	section string
+	lines   func(it *item, hostInfo *sysinfo.Info) []infoLine
+}
+
+// Sections of the items, in display order (see LayoutConfig.Sections)
+const (
+	sectionHardware    = "Hardware"
+	sectionSoftware    = "Software"
+	sectionNetwork     = "Network"
+	sectionEnvironment = "Environment"
+)
+
+var sections = []string{sectionHardware, sectionSoftware, sectionNetwork, sectionEnvironment}
+
+// colored returns the value in the color (see theme.go), if any.
+// The colors within the value go back to this color.
+func colored(color, value string) string {
+	if color == "" || value == "" {
+		return value
+	}
+	return color + strings.ReplaceAll(value, colorNormal, colorNormal+color) + colorNormal
+}
+
+// A line of information, as displayed in plain text.
+type infoLine struct {
+	Nerd  string
+	Title string
+	Value string
+}
+
+// line returns a line of information with the title of the item.
+func (it *item) line(value string) infoLine {
+	return infoLine{Nerd: it.nerd, Title: it.title, Value: value}
+}
+
+// All the items we can display, by name.
+var items = map[string]*item{}
+
+func init() {
+	for _, it := range []*item{
+		/* ---------- System Profiler Data (cached data) ---------- */
+		cpuItem,
+		gpuItem,
+		modelItem,
+		memoryItem,
+		serialNumberItem,
+		/* ---------- System Profiler Data (non-cached data) ---------- */
+		batteryItem,
+		diskItem,
+		memoryUsageItem,
+		volumesItem,
+		displayItem,
+		hostnameItem,
+		osItem,
+		systemIntegrityItem,
+		uptimeItem,
+		userItem,
+		/* ---------- Other Data ---------- */
+		datetimeItem,
+		publicIpItem,
+		softwareItem,
+		terminalItem,
+		weatherItem,
+	} {
+		items[it.name] = it
+	}
+}
+
+/* ---------- System Profiler Data (cached data) ---------- */
+
+var cpuItem = &item{
+	name:    "cpu",
+	title:   "CPU",
+	nerd:    "",
+	section: sectionHardware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		var cpuCoreInfo string
+		if strings.HasPrefix(hostInfo.Cpu.Model, "Apple") {
+			cpuCoreInfo = fmt.Sprintf("%s %d cores (%d P and %d E)",
+				hostInfo.Cpu.Model,
+				hostInfo.Cpu.Cores,
+				hostInfo.Cpu.PerformanceCores,
+				hostInfo.Cpu.EfficiencyCores,
+			)
+		} else if goos == "linux" {
+			// /proc/cpuinfo: the model does not include the number of cores
+			cpuCoreInfo = fmt.Sprintf("%s %d cores", hostInfo.Cpu.Model, hostInfo.Cpu.Cores)
+		} else {
+			// Intel CPU: The model also includes the number of cores
+			// (Ex: "6-Core Intel Core i7")
+			cpuCoreInfo = hostInfo.Cpu.Model
+		}
+		return []infoLine{it.line(cpuCoreInfo)}
+	},
+}
+
+var gpuItem = &item{
+	name:    "gpu",
+	title:   "GPU",
+	nerd:    "",
+	section: sectionHardware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		// No GPU (Ex. headless Linux server)
+		if len(hostInfo.Gpus) == 0 {
+			return []infoLine{it.line("None")}
+		}
+		var lines []infoLine
+		for i, gpu := range hostInfo.Gpus {
+			// The model usually starts with the vendor, Ex. "Intel UHD Graphics 630"
+			name := gpu.Model
+			if gpu.Vendor != "" && !strings.HasPrefix(name, gpu.Vendor) {
+				name = gpu.Vendor + " " + name
+			}
+			parts := []string{name}
+			if gpu.Cores > 0 {
+				parts = append(parts, fmt.Sprintf("%d cores", gpu.Cores))
+			}
+			if gpu.VRAM != "" {
+				parts = append(parts, gpu.VRAM+" VRAM")
+			}
+			if gpu.Metal != "" {
+				parts = append(parts, gpu.Metal)
+			}
+			line := it.line(strings.Join(parts, " | "))
+			line.Title = fmt.Sprintf("%s #%d", line.Title, i+1)
+			lines = append(lines, line)
+		}
+		return lines
+	},
+}
+
+var modelItem = &item{
+	name:    "model",
+	title:   "Model",
+	nerd:    "",
+	section: sectionHardware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		return []infoLine{it.line(fmt.Sprintf("%s %s (%s) %s",
+			hostInfo.Model.Name,
+			hostInfo.Model.SubName,
+			hostInfo.Model.Date,
+			hostInfo.Model.Number,
+		))}
+	},
+}
+
+var memoryItem = &item{
+	name:    "memory",
+	title:   "Memory",
+	nerd:    "",
+	section: sectionHardware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		return []infoLine{it.line(fmt.Sprintf("%d %s %s",
+			hostInfo.Memory.Amount,
+			hostInfo.Memory.Unit,
+			hostInfo.Memory.MemType,
+		))}
+	},
+}
+
+var serialNumberItem = &item{
+	name:    "serial_number",
+	title:   "Serial",
+	nerd:    "",
+	section: sectionHardware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		return []infoLine{it.line(*hostInfo.SerialNumber)}
+	},
+}
+
+/* ---------- System Profiler Data (non-cached data) ---------- */
+
+var batteryItem = &item{
+	name:    "battery",
+	title:   "Battery",
+	nerd:    "󰂄",
+	section: sectionHardware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		// No battery (e.g. Linux desktop)
+		if hostInfo.Battery == nil {
+			return []infoLine{it.line("None")}
+		}
+		var charging []string
+		switch {
+		case hostInfo.Battery.FullyCharged:
+			charging = append(charging, "fully charged")
+		case hostInfo.Battery.Charging:
+			charging = append(charging, "charging")
+		default:
+			charging = append(charging, "discharging")
+		}
+		if hostInfo.Battery.AtWarnLevel {
+			charging = append(charging, "low")
+		}
+		// The remaining time is an estimate, not always available
+		if minutes := hostInfo.Battery.TimeToEmptyMinutes; minutes > 0 {
+			charging = append(charging, fmt.Sprintf("%d:%02d left", minutes/60, minutes%60))
+		} else if minutes := hostInfo.Battery.TimeToFullMinutes; minutes > 0 {
+			charging = append(charging, fmt.Sprintf("%d:%02d to full", minutes/60, minutes%60))
+		}
+		health := it.line(hostInfo.Battery.Health)
+		health.Title = fmt.Sprintf("%s health", health.Title)
+		status := fmt.Sprintf("%d%%", hostInfo.Battery.StatusPercent)
+		if showBar(barBattery) {
+			status = renderBar(barBattery, float64(hostInfo.Battery.StatusPercent))
+		}
+		capacity := fmt.Sprintf("%d%% capacity", hostInfo.Battery.CapacityPercent)
+		if showBar(barCapacity) {
+			capacity = "capacity " + renderBar(barCapacity, float64(hostInfo.Battery.CapacityPercent))
+		}
+		lines := []infoLine{
+			it.line(fmt.Sprintf("%s (%s) | %s", status, strings.Join(charging, ", "), capacity)),
+			health,
+		}
+		// The cycle count is not reported by all Linux drivers
+		if hostInfo.Battery.CycleCount > 0 {
+			cycles := it.line(fmt.Sprintf("%d", hostInfo.Battery.CycleCount))
+			cycles.Title = fmt.Sprintf("%s cycles", cycles.Title)
+			lines = append(lines, cycles)
+		}
+		if adapter := hostInfo.Battery.ACAdapter; adapter != nil {
+			var value string
+			switch {
+			case !adapter.Connected:
+				value = "Not connected"
+			case adapter.Watts > 0:
+				value = fmt.Sprintf("%d W (connected)", adapter.Watts)
+			default:
+				value = "Connected"
+			}
+			line := it.line(value)
+			line.Title = "AC adapter"
+			lines = append(lines, line)
+		}
+		return lines
+	},
+}
+
+var diskItem = &item{
+	name:    "disk",
+	title:   "Disk",
+	nerd:    "󰋊",
+	section: sectionHardware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		disk := fmt.Sprintf("%.2f TB (%.2f TB available)", hostInfo.Disk.TotalTB, hostInfo.Disk.FreeTB)
+		if showBar(barDisk) && hostInfo.Disk.TotalTB > 0 {
+			used := 100 * (hostInfo.Disk.TotalTB - hostInfo.Disk.FreeTB) / hostInfo.Disk.TotalTB
+			disk = fmt.Sprintf("%s %s", disk, renderBar(barDisk, float64(used)))
+		}
+		lines := []infoLine{it.line(disk)}
+		// SMART status is not available on Linux
+		if hostInfo.Disk.SmartStatus != "" {
+			smart := it.line(hostInfo.Disk.SmartStatus)
+			smart.Title = fmt.Sprintf("%s SMART", smart.Title)
+			lines = append(lines, smart)
+		}
+		return lines
+	},
+}
+
+var memoryUsageItem = &item{
+	name:    "memory_usage",
+	title:   "Memory usage",
+	nerd:    "",
+	section: sectionHardware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		usage := hostInfo.MemoryUsage
+		used := fmt.Sprintf("%s / %s (%s%%)", formatMemory(usage.UsedBytes), formatMemory(usage.TotalBytes), formatFloat(usage.UsedPercent))
+		if showBar(barMemory) {
+			used = fmt.Sprintf("%s / %s %s", formatMemory(usage.UsedBytes), formatMemory(usage.TotalBytes), renderBar(barMemory, usage.UsedPercent))
+		}
+		if usage.Pressure != "" {
+			used = fmt.Sprintf("%s | pressure %s", used, usage.Pressure)
+		}
+		lines := []infoLine{it.line(used)}
+
+		// Wired and compressed memory are only known on macOS
+		var details []string
+		for _, detail := range []struct {
+			bytes uint64
+			name  string
+		}{
+			{usage.WiredBytes, "wired"},
+			{usage.CompressedBytes, "compressed"},
+			{usage.CachedBytes, "cached"},
+		} {
+			if detail.bytes > 0 {
+				details = append(details, fmt.Sprintf("%s %s", formatMemory(detail.bytes), detail.name))
+			}
+		}
+		if len(details) > 0 {
+			line := it.line(strings.Join(details, " | "))
+			line.Title = "Memory details"
+			lines = append(lines, line)
+		}
+		if usage.SwapTotalBytes > 0 {
+			swap := fmt.Sprintf("%s / %s", formatMemory(usage.SwapUsedBytes), formatMemory(usage.SwapTotalBytes))
+			if showBar(barSwap) {
+				swap += " " + renderBar(barSwap, 100*float64(usage.SwapUsedBytes)/float64(usage.SwapTotalBytes))
+			}
+			line := it.line(swap)
+			line.Title = "Swap"
+			lines = append(lines, line)
+		}
+		return lines
+	},
+}
+
+var volumesItem = &item{
+	name:    "volumes",
+	title:   "Volume",
+	nerd:    "󰋊",
+	section: sectionHardware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		var lines []infoLine
+		for i, volume := range hostInfo.Volumes {
+			// formatBytes cannot fail with a number
+			total, _ := formatBytes(volume.TotalBytes)
+			free, _ := formatBytes(volume.FreeBytes)
+			parts := []string{
+				fmt.Sprintf("%s (%s)", volume.Name, volume.MountPoint),
+				fmt.Sprintf("%s (%s available)", total, free),
+			}
+			if showBar(barVolumes) && volume.TotalBytes > 0 {
+				used := 100 * float64(volume.TotalBytes-volume.FreeBytes) / float64(volume.TotalBytes)
+				parts[1] = fmt.Sprintf("%s %s", parts[1], renderBar(barVolumes, used))
+			}
+			details := []string{volume.FileSystem}
+			if volume.Encrypted != nil && *volume.Encrypted {
+				details = append(details, "encrypted")
+			}
+			parts = append(parts, strings.Join(details, ", "))
+			// SMART status is not available on Linux, nor for some external drives
+			if volume.SmartStatus != "" {
+				parts = append(parts, "SMART "+volume.SmartStatus)
+			}
+			line := it.line(strings.Join(parts, " | "))
+			line.Title = fmt.Sprintf("%s #%d", line.Title, i+1)
+			lines = append(lines, line)
+		}
+		if lines == nil {
+			return []infoLine{it.line("None")}
+		}
+		return lines
+	},
+}
+
+var displayItem = &item{
+	name:    "display",
+	title:   "Display",
+	nerd:    "",
+	section: sectionHardware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		var lines []infoLine
+		for i, display := range hostInfo.Displays {
+			d := fmt.Sprintf("%d x %d | %d x %d",
+				display.PixelsWidth,
+				display.PixelsHeight,
+				display.ResolutionWidth,
+				display.ResolutionHeight,
+			)
+			// The refresh rate and the scale factor are not known on Linux
+			if display.RefreshRateHz > 0 {
+				d = fmt.Sprintf("%s @ %.0f Hz", d, display.RefreshRateHz)
+			}
+			if display.ScaleFactor > 1 {
+				d = fmt.Sprintf("%s (%sx)", d, strconv.FormatFloat(display.ScaleFactor, 'f', -1, 64))
+			}
+			if display.Name != "" {
+				d = fmt.Sprintf("%s | %s", display.Name, d)
+			}
+			var details []string
+			if display.BuiltIn {
+				details = append(details, "built-in")
+			} else if display.Connection != "" {
+				details = append(details, display.Connection)
+			}
+			for _, flag := range []struct {
+				set  bool
+				name string
+			}{
+				{display.Main, "main"},
+				{display.Mirrored, "mirrored"},
+				{display.HDR, "HDR"},
+				{display.ProMotion, "ProMotion"},
+			} {
+				if flag.set {
+					details = append(details, flag.name)
+				}
+			}
+			if len(details) > 0 {
+				d = fmt.Sprintf("%s | %s", d, strings.Join(details, ", "))
+			}
+			line := it.line(d)
+			line.Title = fmt.Sprintf("%s #%d", line.Title, i+1)
+			lines = append(lines, line)
+		}
+		// No display (Ex. headless Mac mini or server)
+		if lines == nil {
+			return []infoLine{it.line("None")}
+		}
+		return lines
+	},
+}
+
+var hostnameItem = &item{
+	name:    "hostname",
+	title:   "Hostname",
+	nerd:    "",
+	section: sectionNetwork,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		return []infoLine{it.line(hostInfo.Hostname)}
+	},
+}
+
+var osItem = &item{
+	name:    "os",
+	title:   "OS",
+	nerd:    "",
+	section: sectionSoftware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		var systemBuild string
+		// There is no build number on most Linux distributions.
+		if hostInfo.Os.SystemBuild != "" {
+			systemBuild = fmt.Sprintf(" (%s)", hostInfo.Os.SystemBuild)
+		}
+		return []infoLine{it.line(fmt.Sprintf("%s %s %s%s %s %s",
+			hostInfo.Os.System,
+			hostInfo.Os.SystemVersionCodeNname,
+			hostInfo.Os.SystemVersion,
+			systemBuild,
+			hostInfo.Os.KernelType,
+			hostInfo.Os.KernelVersion,
+		))}
+	},
+}
+
+var systemIntegrityItem = &item{
+	name:    "system_integrity",
+	title:   "macOS SIP",
+	nerd:    "",
+	section: sectionSoftware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		return []infoLine{it.line(capitalizeFirstLetter(
+			strings.TrimPrefix(hostInfo.SystemIntegrity, "integrity_"),
+		))}
+	},
+}
+
+var uptimeItem = &item{
+	name:    "uptime",
+	title:   "Uptime",
+	nerd:    "",
+	section: sectionSoftware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		return []infoLine{it.line(hostInfo.Uptime)}
+	},
+}
+
+var userItem = &item{
+	name:    "user",
+	title:   "User",
+	nerd:    "",
+	section: sectionEnvironment,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		return []infoLine{it.line(fmt.Sprintf("%s (%s)", hostInfo.User.RealName, hostInfo.User.Login))}
+	},
+}
+
+/* ---------- Other Data ---------- */
+
+var datetimeItem = &item{
+	name:    "datetime",
+	title:   "Date/Time",
+	nerd:    "",
+	section: sectionEnvironment,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		return []infoLine{it.line(hostInfo.Datetime)}
+	},
+}
+
+var publicIpItem = &item{
+	name:    "public_ip",
+	title:   "Public IP",
+	nerd:    "󱦂",
+	section: sectionNetwork,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		if hostInfo.PublicIp == nil {
+			return []infoLine{it.line("Unknown")}
+		}
+		// Case we have a "Unknown" country (any error in function getPublicIpInfo)
+		if len(hostInfo.PublicIp.Country) == 0 {
+			return []infoLine{it.line(hostInfo.PublicIp.IP)}
+		}
+		return []infoLine{it.line(fmt.Sprintf("%s (%s)",
+			hostInfo.PublicIp.IP,
+			hostInfo.PublicIp.Country,
+		))}
+	},
+}
+
+var softwareItem = &item{
+	name:    "software",
+	title:   "Software",
+	nerd:    "",
+	section: sectionSoftware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		return []infoLine{it.line(fmt.Sprintf("%d Apps | %d Formulae | %d Casks",
+			hostInfo.Software.NumApps,
+			hostInfo.Software.NumBrewFormulae,
+			hostInfo.Software.NumBrewCasks,
+		))}
+	},
+}
+
+var terminalItem = &item{
+	name:    "terminal",
+	title:   "Terminal",
+	nerd:    "",
+	section: sectionSoftware,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		return []infoLine{it.line(hostInfo.Terminal)}
+	},
+}
+
+var weatherItem = &item{
+	name:    "weather",
+	title:   "Weather",
+	nerd:    "󰖙",
+	section: sectionEnvironment,
+	lines: func(it *item, hostInfo *sysinfo.Info) []infoLine {
+		var location string
+
+		if hostInfo.Weather.LocationName != "" {
+			location = fmt.Sprintf("%s, %s", hostInfo.Weather.LocationName, hostInfo.Weather.LocationCountryCode)
+		} else if hostInfo.PublicIp != nil {
+			location = fmt.Sprintf("%s, %s", hostInfo.PublicIp.City, hostInfo.PublicIp.CountryCode)
+		} else {
+			location = fmt.Sprintf("(%f, %f)", hostInfo.Weather.Latitude, hostInfo.Weather.Longitude)
+		}
+		return []infoLine{
+			it.line(fmt.Sprintf("%s: %s",
+				location,
+				hostInfo.Weather.CurrentWeather,
+			)),
+			{
+				Nerd:  "",
+				Title: "Temp. | Wind",
+				Value: fmt.Sprintf(
+					"%s (%s) %s | %s %.0f (%.0f) %s",
+					formatFloat(roundToNearestHalf(hostInfo.Weather.Temperature)),
+					formatFloat(roundToNearestHalf(hostInfo.Weather.FeelsLike)),
+					hostInfo.Weather.TempUnit,
+					windArrow(hostInfo.Weather.WindDirection),
+					hostInfo.Weather.WindSpeed,
+					hostInfo.Weather.WindGusts,
+					hostInfo.Weather.WindUnit,
+				),
+			},
+		}
+	},
+}
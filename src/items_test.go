@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"minfo/pkg/sysinfo"
+)
+
+func TestItemsAreDisplayed(t *testing.T) {
+	for _, name := range sysinfo.Items() {
+		if _, ok := items[name]; !ok {
+			t.Errorf("Item '%s' has no display", name)
+		}
+	}
+}
+
+func TestItemsHaveNerdSymbol(t *testing.T) {
+	for name, it := range items {
+		if it.nerd == "" {
+			t.Errorf("Item '%s' has no nerd symbol", name)
+		}
+	}
+}
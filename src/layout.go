@@ -0,0 +1,206 @@
+package main
+
+/*
+This file contains the layout of the text output (see LayoutConfig: position of the logo,
+separator between the titles and the values, alignment of the titles), depending on
+the width of the terminal:
+
+  - the logo beside the information (on the left or the right), the values too long
+    being truncated (with an ellipsis) or wrapped (the following lines aligned under
+    the value), see Config.Overflow;
+  - the logo above the information, if requested, or when the values would be
+    too narrow beside the logo;
+  - the information only, when even the logo is wider than the terminal.
+
+Lengths are counted in runes, ignoring the ANSI escape codes.
+*/
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// What to do with the values too long for the terminal (see Config.Overflow)
+const (
+	overflowTruncate = "truncate"
+	overflowWrap     = "wrap"
+)
+
+// Positions of the logo (see LayoutConfig.LogoPosition)
+const (
+	positionLeft  = "left"
+	positionRight = "right"
+	positionTop   = "top"
+)
+
+// Alignments of the titles (see LayoutConfig.TitleAlign)
+const (
+	alignLeft  = "left"
+	alignRight = "right"
+)
+
+// Layouts of the logo and the information
+const (
+	layoutSideBySide = iota
+	layoutStacked
+	layoutNoLogo
+)
+
+// Spaces between the logo and the information
+const logoGap = 2
+
+// Minimum width of the values beside the logo: below it, the logo is displayed above.
+const minValueWidth = 20
+
+const ellipsis = "…"
+
+// terminalWidth returns the width of the terminal (or of $COLUMNS if the output
+// is not a terminal), or 0 if it is unknown: the lines are then never shortened.
+func terminalWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 0
+}
+
+// chooseLayout returns the layout fitting the information (with titles of padding
+// characters) and a logo of logoWidth characters in width characters.
+func chooseLayout(width, logoWidth, padding int, infoLines [][]string, logoPosition string) int {
+	if logoPosition == positionTop {
+		if width <= 0 || logoWidth <= width {
+			return layoutStacked
+		}
+		return layoutNoLogo
+	}
+	if width <= 0 {
+		return layoutSideBySide
+	}
+	// Width of the information, the values being shortened up to minValueWidth
+	infoWidth := 0
+	for _, line := range infoLines {
+		if len(line) == 1 {
+			infoWidth = max(infoWidth, min(visibleLength(line[0]), padding+minValueWidth))
+		} else {
+			infoWidth = max(infoWidth, padding+min(visibleLength(line[3]), minValueWidth))
+		}
+	}
+	if logoWidth+logoGap+infoWidth <= width {
+		return layoutSideBySide
+	}
+	if logoWidth <= width {
+		return layoutStacked
+	}
+	return layoutNoLogo
+}
+
+// fitInfoLines truncates or wraps (see Config.Overflow) the lines longer than width,
+// the titles taking padding characters. The wrapped parts of a value are added as
+// lines without title. The header and section titles are always truncated.
+func fitInfoLines(infoLines [][]string, width, padding int, overflow string) [][]string {
+	valueWidth := max(width-padding, 1)
+	fitted := make([][]string, 0, len(infoLines))
+	for _, line := range infoLines {
+		if len(line) == 1 {
+			fitted = append(fitted, []string{truncateVisible(line[0], max(width, 1))})
+			continue
+		}
+		if visibleLength(line[3]) <= valueWidth {
+			fitted = append(fitted, line)
+			continue
+		}
+		if overflow != overflowWrap {
+			fitted = append(fitted, []string{line[0], line[1], line[2], truncateVisible(line[3], valueWidth)})
+			continue
+		}
+		for i, part := range wrapVisible(line[3], valueWidth) {
+			if i == 0 {
+				fitted = append(fitted, []string{line[0], line[1], line[2], part})
+			} else {
+				fitted = append(fitted, []string{"", "", "", part})
+			}
+		}
+	}
+	return fitted
+}
+
+// visibleLength returns the number of characters of s, without the ANSI escape codes.
+func visibleLength(s string) int {
+	return utf8.RuneCountInString(reANSI.ReplaceAllString(s, ""))
+}
+
+// splitVisible splits s after n visible characters, keeping the ANSI escape codes.
+func splitVisible(s string, n int) (head, tail string) {
+	escapes := reANSI.FindAllStringIndex(s, -1)
+	count := 0
+	for i := 0; i < len(s); {
+		if len(escapes) > 0 && escapes[0][0] == i {
+			i = escapes[0][1]
+			escapes = escapes[1:]
+			continue
+		}
+		if count == n {
+			return s[:i], s[i:]
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		count++
+	}
+	return s, ""
+}
+
+// closeColor ends a part of a colored value with a reset, and returns
+// the escape code to apply to the rest of the value (if any).
+func closeColor(head string) (string, string) {
+	escapes := reANSI.FindAllString(head, -1)
+	if len(escapes) == 0 {
+		return head, ""
+	}
+	last := escapes[len(escapes)-1]
+	if last == colorNormal {
+		return head, ""
+	}
+	return head + colorNormal, last
+}
+
+// truncateVisible shortens s to n visible characters, the last one being an ellipsis.
+func truncateVisible(s string, n int) string {
+	if visibleLength(s) <= n {
+		return s
+	}
+	head, _ := splitVisible(s, n-1)
+	head, _ = closeColor(head)
+	return head + ellipsis
+}
+
+// wrapVisible splits s into lines of at most n visible characters, at spaces if possible.
+func wrapVisible(s string, n int) []string {
+	var lines []string
+	for visibleLength(s) > n {
+		// Last space within the first n+1 characters (a space right after them is a good break)
+		plain := []rune(reANSI.ReplaceAllString(s, ""))
+		breakAt := strings.LastIndex(string(plain[:n+1]), " ")
+		if breakAt > 0 {
+			breakAt = utf8.RuneCountInString(string(plain[:n+1])[:breakAt])
+		}
+		var head, tail string
+		if breakAt > 0 {
+			head, tail = splitVisible(s, breakAt)
+			// Drop the space (but not the escape codes before it)
+			space, rest := splitVisible(tail, 1)
+			tail = strings.Replace(space, " ", "", 1) + rest
+		} else {
+			head, tail = splitVisible(s, n)
+		}
+		head, color := closeColor(head)
+		lines = append(lines, head)
+		s = color + tail
+	}
+	return append(lines, s)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTruncateVisible(t *testing.T) {
+	tests := []struct {
+		value    string
+		width    int
+		expected string
+	}{
+		{"macOS Sequoia", 20, "macOS Sequoia"},
+		{"macOS Sequoia", 8, "macOS S…"},
+		// The escape codes are not counted, and the color is reset before the ellipsis.
+		{colorDim + "unavailable (timeout)" + colorNormal, 10, colorDim + "unavailab" + colorNormal + "…"},
+	}
+	for _, test := range tests {
+		if actual := truncateVisible(test.value, test.width); actual != test.expected {
+			t.Errorf("truncateVisible(%q, %d) = %q, expected %q", test.value, test.width, actual, test.expected)
+		}
+	}
+}
+
+func TestWrapVisible(t *testing.T) {
+	tests := []struct {
+		value    string
+		width    int
+		expected []string
+	}{
+		{"MacBook Pro 16-inch (Nov 2024)", 12, []string{"MacBook Pro", "16-inch (Nov", "2024)"}},
+		{"Z1FW0008GSM/A", 5, []string{"Z1FW0", "008GS", "M/A"}},
+		// The color goes on on the next line.
+		{colorDim + "unavailable (timeout)" + colorNormal, 12, []string{colorDim + "unavailable" + colorNormal, colorDim + "(timeout)" + colorNormal}},
+	}
+	for _, test := range tests {
+		if actual := wrapVisible(test.value, test.width); !slices.Equal(actual, test.expected) {
+			t.Errorf("wrapVisible(%q, %d) = %q, expected %q", test.value, test.width, actual, test.expected)
+		}
+	}
+}
+
+func TestChooseLayout(t *testing.T) {
+	infoLines := [][]string{{"", "CPU", "", "Apple M4 Max 16 cores (12 P and 4 E)"}}
+	tests := []struct {
+		width    int
+		expected int
+	}{
+		{0, layoutSideBySide}, // unknown width
+		{120, layoutSideBySide},
+		{60, layoutStacked},
+		{20, layoutNoLogo},
+	}
+	for _, test := range tests {
+		if actual := chooseLayout(test.width, 30, 10, infoLines, positionLeft); actual != test.expected {
+			t.Errorf("chooseLayout(%d) = %d, expected %d", test.width, actual, test.expected)
+		}
+	}
+	// The logo is displayed above the information if it fits.
+	if actual := chooseLayout(120, 30, 10, infoLines, positionTop); actual != layoutStacked {
+		t.Errorf("Expected the stacked layout, got %d", actual)
+	}
+}
+
+func TestFitInfoLines(t *testing.T) {
+	infoLines := [][]string{{"jdoe@jdoe-laptop"}, {colorTitle, "OS", colorNormal, "macOS Sequoia 15.2"}}
+	fitted := fitInfoLines(infoLines, 14, 4, overflowWrap)
+	expected := [][]string{{"jdoe@jdoe-lap…"}, {colorTitle, "OS", colorNormal, "macOS"}, {"", "", "", "Sequoia"}, {"", "", "", "15.2"}}
+	if !slices.EqualFunc(fitted, expected, slices.Equal) {
+		t.Errorf("Expected %q, got %q", expected, fitted)
+	}
+}
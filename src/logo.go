@@ -0,0 +1,355 @@
+package main
+
+/*
+This file contains the loading and rendering of the logos.
+
+A logo is a YAML file, with colors defined per line (or per span of characters
+within a line) for truecolor, 256 colors and 16 colors terminals:
+
+	lines:
+	  - text: "   ####"
+	    color: "#61BB46"  # truecolor (hex)
+	    color256: "28"    # 256 colors palette index
+	    color16: "32"     # ANSI SGR code(s)
+	  - spans:
+	      - text: "###"
+	        color: "#FDB827"
+	      - text: "###"
+	        color: "#E03A3E"
+
+Background colors are defined the same way (background, background256 and
+background16, Ex. "40").
+
+The variant used depends on the colors supported by the terminal. A color
+missing for the terminal's level falls back to the lower level (Ex. color256
+is used on a truecolor terminal if color is not defined), and the span colors
+fall back to the colors of their line.
+
+The legacy logos (raw lines with ANSI escape codes) are still accepted, and the
+legacy Apple logos (apple-256colors and apple-16colors) are built-in.
+
+The logos of the logos/ directory are embedded into the binary, and can be
+used by name (Ex. "apple" or "builtin:apple-nocolor"), instead of a path.
+By default ("auto"), the logo is chosen from the model and the operating system.
+*/
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"minfo/pkg/sysinfo"
+
+	"github.com/jwalton/go-supportscolor"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed logos
+var builtinLogos embed.FS
+
+// Prefix of the built-in logos names, to use them even if a file has the same name.
+const builtinLogoPrefix = "builtin:"
+
+// Logos formerly installed with minfo (Ex. $HOMEBREW_PREFIX/share/minfo/apple-256colors),
+// now built-in: their paths, still found in configuration files, load the built-in logos.
+var installedLogos = []string{"apple-256colors", "apple-16colors", "apple-nocolor"}
+
+// Logo chosen from the collected information (see autoLogo).
+const logoAuto = "auto"
+
+// Items used to choose the logo automatically.
+var autoLogoItems = []string{"model", "os"}
+
+// Built-in logos of the Mac models, by (lowercase) model name.
+var modelLogos = []struct {
+	name string
+	logo string
+}{
+	{"macbook", "macbook"},
+	{"imac", "imac"},
+	{"mac mini", "mac-mini"},
+	{"mac studio", "mac-studio"},
+}
+
+// Built-in logos of the Linux distributions, by ID of /etc/os-release.
+var distroLogos = map[string]string{
+	"alpine": "alpine",
+	"arch":   "arch",
+	"debian": "debian",
+	"fedora": "fedora",
+	"nixos":  "nixos",
+	"ubuntu": "ubuntu",
+}
+
+// Color levels of a terminal, from no colors to truecolor.
+const (
+	colorLevelNone = iota
+	colorLevel16
+	colorLevel256
+	colorLevelTrue
+)
+
+// terminalColorLevel returns the color level supported by the standard output.
+func terminalColorLevel() int {
+	switch supportscolor.Stdout().Level {
+	case supportscolor.Ansi16m:
+		return colorLevelTrue
+	case supportscolor.Ansi256:
+		return colorLevel256
+	case supportscolor.Basic:
+		return colorLevel16
+	}
+	return colorLevelNone
+}
+
+// autoLogo returns the name of the built-in logo matching the host:
+// the Mac model, then the Apple logo on macOS, then the Linux distribution
+// (or the one it is derived from, Ex. ubuntu for Linux Mint), then a generic logo.
+func autoLogo(hostInfo *sysinfo.Info) string {
+	if hostInfo.Model != nil {
+		name := strings.ToLower(hostInfo.Model.Name)
+		for _, m := range modelLogos {
+			if strings.Contains(name, m.name) {
+				return m.logo
+			}
+		}
+	}
+	if goos == "darwin" {
+		return "apple"
+	}
+	if hostInfo.Os != nil {
+		for _, id := range append([]string{hostInfo.Os.Id}, hostInfo.Os.IdLike...) {
+			if logo, ok := distroLogos[id]; ok {
+				return logo
+			}
+		}
+	}
+	return "generic"
+}
+
+// builtinLogoNames returns the names of the built-in logos
+// (file names without extension), sorted.
+func builtinLogoNames() []string {
+	entries, _ := builtinLogos.ReadDir("logos")
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// readBuiltinLogo returns the content of the built-in logo name.
+func readBuiltinLogo(name string) ([]byte, error) {
+	entries, _ := builtinLogos.ReadDir("logos")
+	for _, entry := range entries {
+		if strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())) == name {
+			return fs.ReadFile(builtinLogos, "logos/"+entry.Name())
+		}
+	}
+	return nil, fmt.Errorf("unknown built-in logo: %s (available: %s)", name, strings.Join(builtinLogoNames(), ", "))
+}
+
+// loadLogo reads a logo (YAML or legacy), and returns its lines rendered
+// for the color level. logo is either the name of a built-in logo,
+// or the path of a logo file.
+func loadLogo(logo string, level int) ([]string, error) {
+	var data []byte
+	var err error
+	if name, ok := strings.CutPrefix(logo, builtinLogoPrefix); ok {
+		data, err = readBuiltinLogo(name)
+	} else if isBuiltinLogo(logo) {
+		data, err = readBuiltinLogo(logo)
+	} else {
+		data, err = os.ReadFile(logo)
+		if name := filepath.Base(logo); errors.Is(err, fs.ErrNotExist) && slices.Contains(installedLogos, name) {
+			data, err = readBuiltinLogo(name)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseLogo(data, level)
+}
+
+// isBuiltinLogo returns true if logo is the name of a built-in logo.
+// A file with the same name can be used with a path, Ex. "./apple".
+func isBuiltinLogo(logo string) bool {
+	if strings.ContainsRune(logo, os.PathSeparator) {
+		return false
+	}
+	return slices.Contains(builtinLogoNames(), logo)
+}
+
+// listLogos writes the names and a preview of all the built-in logos.
+func listLogos(w io.Writer) error {
+	level := terminalColorLevel()
+	for _, name := range builtinLogoNames() {
+		logoLines, err := loadLogo(builtinLogoPrefix+name, level)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\n\n", name)
+		for _, line := range logoLines {
+			if reANSI.MatchString(line) && !strings.HasSuffix(line, colorNormal) {
+				line += colorNormal // the colors of a legacy logo are not reset
+			}
+			fmt.Fprintln(w, line)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// parseLogo parses a logo (YAML or legacy), and returns its lines
+// rendered for the color level.
+func parseLogo(data []byte, level int) ([]string, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("Invalid logo (empty)")
+	}
+
+	var l logo
+	if err := yaml.Unmarshal(data, &l); err == nil && len(l.Lines) > 0 {
+		return l.render(level)
+	}
+
+	// Legacy logo: let's remove empty lines and comments
+	var logoLines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		logoLines = append(logoLines, line)
+	}
+	if len(logoLines) == 0 {
+		return nil, fmt.Errorf("Invalid logo (empty)")
+	}
+	return logoLines, nil
+}
+
+// render returns the lines of the logo, with the ANSI escape codes
+// of the color level.
+func (l *logo) render(level int) ([]string, error) {
+	var logoLines []string
+	for i, line := range l.Lines {
+		spans := line.Spans
+		if len(spans) == 0 {
+			spans = []logoSpan{{Text: line.Text}}
+		} else if line.Text != "" {
+			return nil, fmt.Errorf("Invalid logo line %d: both text and spans are defined", i+1)
+		}
+
+		var rendered strings.Builder
+		colored, background := false, false
+		for _, span := range spans {
+			fg, bg, err := span.logoColors.orDefault(line.logoColors).escapeCodes(level)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid logo line %d: %w", i+1, err)
+			}
+			// A foreground color does not reset the background of the previous span.
+			if fg+bg == "" || (background && bg == "") {
+				if colored {
+					rendered.WriteString(colorNormal)
+				}
+				colored = false
+			}
+			if fg+bg != "" {
+				rendered.WriteString(fg + bg)
+				colored = true
+			}
+			background = bg != ""
+			rendered.WriteString(span.Text)
+		}
+		if colored {
+			rendered.WriteString(colorNormal)
+		}
+		logoLines = append(logoLines, rendered.String())
+	}
+	return logoLines, nil
+}
+
+// orDefault returns the colors, where the ones which are not defined
+// are taken from defaults.
+func (c logoColors) orDefault(defaults logoColors) logoColors {
+	if c.Color == "" {
+		c.Color = defaults.Color
+	}
+	if c.Color256 == "" {
+		c.Color256 = defaults.Color256
+	}
+	if c.Color16 == "" {
+		c.Color16 = defaults.Color16
+	}
+	if c.Background == "" {
+		c.Background = defaults.Background
+	}
+	if c.Background256 == "" {
+		c.Background256 = defaults.Background256
+	}
+	if c.Background16 == "" {
+		c.Background16 = defaults.Background16
+	}
+	return c
+}
+
+// escapeCodes returns the ANSI escape codes of the foreground and background
+// colors for the color level, falling back to the lower levels.
+// They're empty if no color applies.
+func (c logoColors) escapeCodes(level int) (fg, bg string, err error) {
+	if fg, err = escapeCode(level, c.Color, c.Color256, c.Color16, 38); err != nil {
+		return "", "", err
+	}
+	if bg, err = escapeCode(level, c.Background, c.Background256, c.Background16, 48); err != nil {
+		return "", "", err
+	}
+	return fg, bg, nil
+}
+
+// escapeCode returns the ANSI escape code of a color for the color level,
+// where sgr is 38 for a foreground color and 48 for a background color.
+func escapeCode(level int, color, color256, color16 string, sgr int) (string, error) {
+	if level >= colorLevelTrue && color != "" {
+		r, g, b, err := parseHexColor(color)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("\u001B[%d;2;%d;%d;%dm", sgr, r, g, b), nil
+	}
+	if level >= colorLevel256 && color256 != "" {
+		n, err := strconv.Atoi(color256)
+		if err != nil || n < 0 || n > 255 {
+			return "", fmt.Errorf("invalid 256 colors index: %s", color256)
+		}
+		return fmt.Sprintf("\u001B[%d;5;%dm", sgr, n), nil
+	}
+	if level >= colorLevel16 && color16 != "" {
+		for _, code := range strings.Split(color16, ";") {
+			if _, err := strconv.Atoi(code); err != nil {
+				return "", fmt.Errorf("invalid ANSI color code: %s", color16)
+			}
+		}
+		return fmt.Sprintf("\u001B[%sm", color16), nil
+	}
+	return "", nil
+}
+
+// parseHexColor parses a "#RRGGBB" color.
+func parseHexColor(color string) (r, g, b uint8, err error) {
+	hex, ok := strings.CutPrefix(color, "#")
+	if !ok || len(hex) != 6 {
+		return 0, 0, 0, errors.New("invalid hex color: " + color)
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, errors.New("invalid hex color: " + color)
+	}
+	return uint8(rgb >> 16), uint8(rgb >> 8), uint8(rgb), nil
+}
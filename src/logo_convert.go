@@ -0,0 +1,318 @@
+package main
+
+/*
+This file contains the conversion of images (PNG or JPEG) into logos
+("minfo logo convert image.png"), with one of the charsets:
+  - blocks: half blocks ("▀"), i.e. 2 pixels per character (foreground and background colors),
+  - ascii: characters from light to dense, depending on the opacity (Ex. on the edges),
+  - braille: braille patterns, i.e. 2x4 dots per character.
+
+The colors are defined for truecolor, and quantized to the 256 colors and 16 colors
+palettes. The color of the top-left pixel, if opaque, is considered as the background
+of the image, which is removed (Ex. the white background of a JPEG logo).
+*/
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Charsets of the converted logos (--charset)
+const (
+	charsetBlocks  = "blocks"
+	charsetAscii   = "ascii"
+	charsetBraille = "braille"
+)
+
+// Default width of the converted logos, in characters (--width)
+const defaultLogoWidth = 40
+
+// Characters of the ascii charset, from light to dense
+const asciiRamp = ".:-=+*#%@"
+
+// Maximum distance to the background color for a pixel to be removed
+const backgroundDistance = 48
+
+// Pixels per character of each charset (width, height)
+var charsetCellSizes = map[string][2]int{
+	charsetBlocks:  {1, 2},
+	charsetAscii:   {1, 2},
+	charsetBraille: {2, 4},
+}
+
+// The 16 colors of the ANSI palette (as defined by xterm)
+var ansiPalette = []color.NRGBA{
+	{0, 0, 0, 255}, {205, 0, 0, 255}, {0, 205, 0, 255}, {205, 205, 0, 255},
+	{0, 0, 238, 255}, {205, 0, 205, 255}, {0, 205, 205, 255}, {229, 229, 229, 255},
+	{127, 127, 127, 255}, {255, 0, 0, 255}, {0, 255, 0, 255}, {255, 255, 0, 255},
+	{92, 92, 255, 255}, {255, 0, 255, 255}, {0, 255, 255, 255}, {255, 255, 255, 255},
+}
+
+// Levels of the 6x6x6 color cube of the 256 colors palette
+var cubeLevels = []int{0, 95, 135, 175, 215, 255}
+
+// logoCell is a character of a converted logo, with its colors (nil if none).
+type logoCell struct {
+	char rune
+	fg   *color.NRGBA
+	bg   *color.NRGBA
+}
+
+// convertLogo converts the image into a logo of width characters,
+// and writes it in the YAML logo format.
+func convertLogo(w io.Writer, imagePath string, width int, charset string) error {
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return fmt.Errorf("cannot decode %s: %w", imagePath, err)
+	}
+
+	fmt.Fprintf(w, "# Converted from %s (%s, %d characters wide)\n", imagePath, charset, width)
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(imageToLogo(img, width, charset)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// imageToLogo converts the image into a logo of width characters.
+func imageToLogo(img image.Image, width int, charset string) *logo {
+	cellSize := charsetCellSizes[charset]
+	bounds := img.Bounds()
+	pixelsWidth := width * cellSize[0]
+	// Characters are about twice as high as wide, hence cells of 1x2 or 2x4 pixels.
+	pixelsHeight := max(1, (pixelsWidth*bounds.Dy()+bounds.Dx()/2)/bounds.Dx())
+	height := (pixelsHeight + cellSize[1] - 1) / cellSize[1]
+	pixels := resizeImage(img, pixelsWidth, height*cellSize[1])
+	removeBackground(pixels)
+
+	l := &logo{}
+	for y := range height {
+		var cells []logoCell
+		for x := range width {
+			cells = append(cells, toLogoCell(pixels, x, y, cellSize, charset))
+		}
+		l.Lines = append(l.Lines, cellsToLine(cells))
+	}
+	// Empty lines at the top and the bottom are not needed
+	isEmpty := func(line logoLine) bool { return line.Text == "" && len(line.Spans) == 0 }
+	for len(l.Lines) > 0 && isEmpty(l.Lines[0]) {
+		l.Lines = l.Lines[1:]
+	}
+	for len(l.Lines) > 0 && isEmpty(l.Lines[len(l.Lines)-1]) {
+		l.Lines = l.Lines[:len(l.Lines)-1]
+	}
+	return l
+}
+
+// resizeImage resizes the image to width x height pixels (averaging the pixels of each area).
+// The pixels outside of the image are transparent.
+func resizeImage(img image.Image, width, height int) [][]color.NRGBA {
+	bounds := img.Bounds()
+	// The image keeps its aspect ratio (the last row of cells may be partially outside)
+	scale := float64(bounds.Dx()) / float64(width)
+	pixels := make([][]color.NRGBA, height)
+	for y := range height {
+		pixels[y] = make([]color.NRGBA, width)
+		for x := range width {
+			x0, x1 := bounds.Min.X+int(float64(x)*scale), bounds.Min.X+int(float64(x+1)*scale)
+			y0, y1 := bounds.Min.Y+int(float64(y)*scale), bounds.Min.Y+int(float64(y+1)*scale)
+			x1, y1 = max(x1, x0+1), max(y1, y0+1)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1 && sy < bounds.Max.Y; sy++ {
+				for sx := x0; sx < x1 && sx < bounds.Max.X; sx++ {
+					// premultiplied by alpha, 16 bits per channel
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			if n == 0 || a == 0 {
+				continue
+			}
+			pixels[y][x] = color.NRGBA{
+				R: uint8(r * 255 / a),
+				G: uint8(g * 255 / a),
+				B: uint8(b * 255 / a),
+				A: uint8(a / n >> 8),
+			}
+		}
+	}
+	return pixels
+}
+
+// removeBackground makes transparent the pixels of the color of the top-left pixel,
+// if it is opaque.
+func removeBackground(pixels [][]color.NRGBA) {
+	background := pixels[0][0]
+	if background.A < 128 {
+		return
+	}
+	for y := range pixels {
+		for x := range pixels[y] {
+			if colorDistance(pixels[y][x], background) <= backgroundDistance {
+				pixels[y][x] = color.NRGBA{}
+			}
+		}
+	}
+}
+
+// toLogoCell returns the character (and its colors) at x, y for the charset.
+func toLogoCell(pixels [][]color.NRGBA, x, y int, cellSize [2]int, charset string) logoCell {
+	visible := func(c color.NRGBA) bool { return c.A >= 128 }
+	switch charset {
+	case charsetBlocks:
+		top, bottom := pixels[2*y][x], pixels[2*y+1][x]
+		switch {
+		case visible(top) && visible(bottom) && top == bottom:
+			return logoCell{char: '█', fg: &top}
+		case visible(top) && visible(bottom):
+			return logoCell{char: '▀', fg: &top, bg: &bottom}
+		case visible(top):
+			return logoCell{char: '▀', fg: &top}
+		case visible(bottom):
+			return logoCell{char: '▄', fg: &bottom}
+		}
+	case charsetAscii:
+		c := averageColor([]color.NRGBA{pixels[2*y][x], pixels[2*y+1][x]})
+		if c.A > 0 {
+			index := int(float64(c.A) / 256 * float64(len(asciiRamp)))
+			return logoCell{char: rune(asciiRamp[min(index, len(asciiRamp)-1)]), fg: &c}
+		}
+	case charsetBraille:
+		// Bits of the dots of a braille pattern, by position (column, row)
+		dots := [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+		var pattern rune
+		var dotColors []color.NRGBA
+		for dy := range cellSize[1] {
+			for dx := range cellSize[0] {
+				if c := pixels[y*cellSize[1]+dy][x*cellSize[0]+dx]; visible(c) {
+					pattern |= dots[dy][dx]
+					dotColors = append(dotColors, c)
+				}
+			}
+		}
+		if pattern != 0 {
+			c := averageColor(dotColors)
+			return logoCell{char: 0x2800 + pattern, fg: &c}
+		}
+	}
+	return logoCell{char: ' '}
+}
+
+// cellsToLine converts the cells into a logo line, with one span
+// per sequence of characters of the same colors.
+func cellsToLine(cells []logoCell) logoLine {
+	// Trailing spaces are not needed
+	for len(cells) > 0 && cells[len(cells)-1].char == ' ' && cells[len(cells)-1].bg == nil {
+		cells = cells[:len(cells)-1]
+	}
+	var line logoLine
+	for _, cell := range cells {
+		colors := cellColors(cell)
+		if n := len(line.Spans); n > 0 && line.Spans[n-1].logoColors == colors {
+			line.Spans[n-1].Text += string(cell.char)
+			continue
+		}
+		line.Spans = append(line.Spans, logoSpan{logoColors: colors, Text: string(cell.char)})
+	}
+	// A single span of text is written as the text of the line
+	if len(line.Spans) == 1 {
+		line = logoLine{logoColors: line.Spans[0].logoColors, Text: line.Spans[0].Text}
+	}
+	return line
+}
+
+// cellColors returns the colors of the cell, for every color level.
+func cellColors(cell logoCell) logoColors {
+	var colors logoColors
+	if cell.fg != nil {
+		colors.Color = hexColor(*cell.fg)
+		colors.Color256 = strconv.Itoa(color256Index(*cell.fg))
+		colors.Color16 = strconv.Itoa(color16Code(*cell.fg, false))
+	}
+	if cell.bg != nil {
+		colors.Background = hexColor(*cell.bg)
+		colors.Background256 = strconv.Itoa(color256Index(*cell.bg))
+		colors.Background16 = strconv.Itoa(color16Code(*cell.bg, true))
+	}
+	return colors
+}
+
+func hexColor(c color.NRGBA) string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// color256Index returns the index of the closest color in the 256 colors palette
+// (the 6x6x6 color cube, or the grayscale ramp).
+func color256Index(c color.NRGBA) int {
+	nearestLevel := func(v uint8) int {
+		best := 0
+		for i, level := range cubeLevels {
+			if abs(int(v)-level) < abs(int(v)-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	r, g, b := nearestLevel(c.R), nearestLevel(c.G), nearestLevel(c.B)
+	cube := color.NRGBA{uint8(cubeLevels[r]), uint8(cubeLevels[g]), uint8(cubeLevels[b]), 255}
+	// Grayscale ramp: 232 (8) to 255 (238), by steps of 10
+	grayStep := min(23, max(0, ((int(c.R)+int(c.G)+int(c.B))/3-8+5)/10))
+	grayLevel := uint8(8 + 10*grayStep)
+	gray := color.NRGBA{grayLevel, grayLevel, grayLevel, 255}
+	if colorDistance(c, gray) < colorDistance(c, cube) {
+		return 232 + grayStep
+	}
+	return 16 + 36*r + 6*g + b
+}
+
+// color16Code returns the SGR code of the closest color in the ANSI palette.
+func color16Code(c color.NRGBA, background bool) int {
+	best := 0
+	for i, paletteColor := range ansiPalette {
+		if colorDistance(c, paletteColor) < colorDistance(c, ansiPalette[best]) {
+			best = i
+		}
+	}
+	code := 30 + best
+	if best >= 8 {
+		code = 90 + best - 8
+	}
+	if background {
+		code += 10
+	}
+	return code
+}
+
+// colorDistance returns the (weighted euclidean) distance between two colors.
+func colorDistance(c1, c2 color.NRGBA) float64 {
+	dr, dg, db := float64(c1.R)-float64(c2.R), float64(c1.G)-float64(c2.G), float64(c1.B)-float64(c2.B)
+	return math.Sqrt((2*dr*dr + 4*dg*dg + 3*db*db) / 9)
+}
+
+// averageColor returns the average of the colors (weighted by their alpha).
+func averageColor(colors []color.NRGBA) color.NRGBA {
+	var r, g, b, a int
+	for _, c := range colors {
+		r, g, b, a = r+int(c.R)*int(c.A), g+int(c.G)*int(c.A), b+int(c.B)*int(c.A), a+int(c.A)
+	}
+	if a == 0 {
+		return color.NRGBA{}
+	}
+	return color.NRGBA{uint8(r / a), uint8(g / a), uint8(b / a), uint8(a / len(colors))}
+}
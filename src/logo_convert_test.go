@@ -0,0 +1,90 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"slices"
+	"testing"
+)
+
+func TestColor256Index(t *testing.T) {
+	tests := []struct {
+		color    color.NRGBA
+		expected int
+	}{
+		{color.NRGBA{255, 0, 0, 255}, 196},
+		{color.NRGBA{0, 0, 0, 255}, 16},
+		{color.NRGBA{95, 135, 0, 255}, 64},
+		{color.NRGBA{128, 128, 128, 255}, 244},
+	}
+	for _, test := range tests {
+		if actual := color256Index(test.color); actual != test.expected {
+			t.Errorf("color256Index(%v) = %d, expected %d", test.color, actual, test.expected)
+		}
+	}
+}
+
+func TestColor16Code(t *testing.T) {
+	red := color.NRGBA{220, 40, 40, 255}
+	if actual := color16Code(red, false); actual != 31 {
+		t.Errorf("Expected 31, got %d", actual)
+	}
+	if actual := color16Code(red, true); actual != 41 {
+		t.Errorf("Expected 41, got %d", actual)
+	}
+	if actual := color16Code(color.NRGBA{250, 250, 250, 255}, false); actual != 97 {
+		t.Errorf("Expected 97, got %d", actual)
+	}
+}
+
+// testImage returns a 4x4 image: a white background, with a red top half
+// and a blue bottom-right pixel.
+func testImage() image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := range 4 {
+		for x := range 4 {
+			img.Set(x, y, color.NRGBA{255, 255, 255, 255})
+		}
+	}
+	for y := range 2 {
+		for x := 1; x < 4; x++ {
+			img.Set(x, y, color.NRGBA{255, 0, 0, 255})
+		}
+	}
+	img.Set(3, 3, color.NRGBA{0, 0, 255, 255})
+	return img
+}
+
+func TestImageToLogo(t *testing.T) {
+	tests := []struct {
+		charset  string
+		width    int
+		expected []string
+	}{
+		// The white background is removed, and trailing spaces are trimmed.
+		{charsetBlocks, 4, []string{" ███", "   ▄"}},
+		{charsetAscii, 4, []string{" @@@", "   +"}},
+		{charsetBraille, 2, []string{"⠘⢛"}},
+	}
+	for _, test := range tests {
+		l := imageToLogo(testImage(), test.width, test.charset)
+		lines, err := l.render(colorLevelNone)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !slices.Equal(lines, test.expected) {
+			t.Errorf("Charset %s: expected %q, got %q", test.charset, test.expected, lines)
+		}
+	}
+
+	// Colors of the half blocks
+	l := imageToLogo(testImage(), 4, charsetBlocks)
+	red := l.Lines[0].Spans[1]
+	if red.Color != "#FF0000" || red.Color256 != "196" || red.Color16 != "91" || red.Background != "" {
+		t.Errorf("Unexpected colors: %+v", red.logoColors)
+	}
+	blue := l.Lines[1].Spans[1]
+	if blue.Text != "▄" || blue.Color != "#0000FF" || blue.Color256 != "21" {
+		t.Errorf("Unexpected colors: %+v", blue)
+	}
+}
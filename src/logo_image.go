@@ -0,0 +1,284 @@
+package main
+
+/*
+This file contains the image logos: a PNG or JPEG image displayed beside the
+information with a terminal graphics protocol (kitty graphics protocol, iTerm2
+inline images, or sixel).
+
+The image takes a given number of cells, which are reserved (filled with spaces)
+in the text, then the image is drawn over them. When the terminal does not support
+any of the protocols (or the output is not a terminal), the text logo is displayed.
+*/
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+// Graphics protocols (see ImageLogoConfig)
+const (
+	protocolAuto   = "auto"
+	protocolKitty  = "kitty"
+	protocolITerm2 = "iterm2"
+	protocolSixel  = "sixel"
+)
+
+// Default width of the image logos, in cells
+const defaultImageLogoWidth = 30
+
+// Size of a cell in pixels, when it cannot be read from the terminal
+const (
+	defaultCellWidth  = 10
+	defaultCellHeight = 20
+)
+
+// ID of the image with the kitty graphics protocol, so that it is replaced
+// when the information is displayed again (Ex. --watch).
+const kittyImageId = 7101
+
+// Terminals supporting sixel graphics (TERM or TERM_PROGRAM)
+var sixelTerminals = []string{"foot", "mlterm", "contour", "WezTerm", "yaft-256color"}
+
+// imageLogo is an image ready to be drawn.
+type imageLogo struct {
+	width  int    // in cells
+	height int    // in cells
+	escape string // escape sequence drawing the image at the cursor
+}
+
+// detectGraphicsProtocol returns the graphics protocol supported by the terminal,
+// or "" if none is (or the output is not a terminal).
+func detectGraphicsProtocol() string {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return ""
+	}
+	// The escape sequences would need to be wrapped to go through tmux and screen.
+	if os.Getenv("TMUX") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		return ""
+	}
+	termName, termProgram := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case termName == "xterm-kitty" || os.Getenv("KITTY_WINDOW_ID") != "" || termProgram == "ghostty":
+		return protocolKitty
+	case termProgram == "iTerm.app" || os.Getenv("LC_TERMINAL") == "iTerm2":
+		return protocolITerm2
+	}
+	for _, name := range sixelTerminals {
+		if termName == name || termProgram == name {
+			return protocolSixel
+		}
+	}
+	return ""
+}
+
+// cellSize returns the size of a cell of the terminal, in pixels.
+func cellSize() (width, height int) {
+	ws, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 || ws.Xpixel == 0 || ws.Ypixel == 0 {
+		return defaultCellWidth, defaultCellHeight
+	}
+	return int(ws.Xpixel / ws.Col), int(ws.Ypixel / ws.Row)
+}
+
+// loadImageLogo reads the image of the configuration, and prepares it for the graphics
+// protocol of the terminal. It returns nil if the terminal does not support images.
+func loadImageLogo(imageConfig *ImageLogoConfig) (*imageLogo, error) {
+	protocol := imageConfig.Protocol
+	if protocol == "" || protocol == protocolAuto {
+		protocol = detectGraphicsProtocol()
+	}
+	if protocol == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(imageConfig.Path)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %w", imageConfig.Path, err)
+	}
+
+	// The height (in cells) keeps the aspect ratio of the image.
+	cellWidth, cellHeight := cellSize()
+	bounds := img.Bounds()
+	width := imageConfig.Width
+	if width <= 0 {
+		width = defaultImageLogoWidth
+	}
+	height := max(1, (width*cellWidth*bounds.Dy()+bounds.Dx()*cellHeight/2)/(bounds.Dx()*cellHeight))
+
+	logo := &imageLogo{width: width, height: height}
+	switch protocol {
+	case protocolKitty:
+		logo.escape, err = kittyImage(img, width, height)
+	case protocolITerm2:
+		logo.escape = iTerm2Image(data, width, height)
+	case protocolSixel:
+		logo.escape = sixelImage(img, width*cellWidth, height*cellHeight)
+	default:
+		return nil, fmt.Errorf("unknown graphics protocol: %s", protocol)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return logo, nil
+}
+
+// placeholder returns the lines of spaces reserving the cells of the image.
+func (l *imageLogo) placeholder() []string {
+	lines := make([]string, l.height)
+	for i := range lines {
+		lines[i] = strings.Repeat(" ", l.width)
+	}
+	return lines
+}
+
+// draw returns the escape sequences drawing the image over its placeholder,
+// which starts at line top and column of the text of totalLines lines (the cursor
+// being at the beginning of the line following the text). The cursor is restored afterwards.
+func (l *imageLogo) draw(top, column, totalLines int) string {
+	right := ""
+	if column > 0 {
+		right = fmt.Sprintf("\u001B[%dC", column)
+	}
+	return fmt.Sprintf("\u001B7\u001B[%dA\r%s%s\u001B8", totalLines-top, right, l.escape)
+}
+
+// kittyImage returns the escape sequences of the kitty graphics protocol displaying
+// the image in width x height cells (the image is sent as PNG, in chunks).
+func kittyImage(img image.Image, width, height int) (string, error) {
+	var data bytes.Buffer
+	if err := png.Encode(&data, img); err != nil {
+		return "", err
+	}
+	encoded := base64.StdEncoding.EncodeToString(data.Bytes())
+
+	var escape strings.Builder
+	const chunkSize = 4096
+	for i := 0; i < len(encoded); i += chunkSize {
+		chunk := encoded[i:min(i+chunkSize, len(encoded))]
+		more := 0
+		if i+chunkSize < len(encoded) {
+			more = 1
+		}
+		if i == 0 {
+			// a=T: transmit and display, C=1: do not move the cursor, q=2: no response
+			fmt.Fprintf(&escape, "\u001B_Ga=T,f=100,i=%d,c=%d,r=%d,C=1,q=2,m=%d;%s\u001B\\",
+				kittyImageId, width, height, more, chunk)
+		} else {
+			fmt.Fprintf(&escape, "\u001B_Gm=%d;%s\u001B\\", more, chunk)
+		}
+	}
+	return escape.String(), nil
+}
+
+// iTerm2Image returns the escape sequence of iTerm2 inline images displaying
+// the image file (PNG or JPEG) in width x height cells.
+func iTerm2Image(data []byte, width, height int) string {
+	return fmt.Sprintf("\u001B]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\u0007",
+		len(data), width, height, base64.StdEncoding.EncodeToString(data))
+}
+
+// sixelImage returns the sixel escape sequence displaying the image resized
+// to width x height pixels, with the colors of the 256 colors palette.
+// Transparent pixels are not drawn.
+func sixelImage(img image.Image, width, height int) string {
+	pixels := resizeImage(img, width, height)
+
+	var escape strings.Builder
+	// P2=1: the transparent pixels keep the background, "1;1 (raster attributes): the pixels are square
+	fmt.Fprintf(&escape, "\u001BP0;1;0q\"1;1;%d;%d", width, height)
+
+	// Palette (only the colors used), with the 256 colors palette indexes
+	indexes := make([][]int, height)
+	defined := map[int]bool{}
+	for y := range height {
+		indexes[y] = make([]int, width)
+		for x := range width {
+			indexes[y][x] = -1
+			if pixels[y][x].A < 128 {
+				continue
+			}
+			index := color256Index(pixels[y][x])
+			indexes[y][x] = index
+			if !defined[index] {
+				defined[index] = true
+				c := color256(index)
+				fmt.Fprintf(&escape, "#%d;2;%d;%d;%d", index, int(c.R)*100/255, int(c.G)*100/255, int(c.B)*100/255)
+			}
+		}
+	}
+
+	// Bands of 6 rows of pixels: one pass per color of the band
+	for top := 0; top < height; top += 6 {
+		var bandColors []int
+		seen := map[int]bool{}
+		for y := top; y < min(top+6, height); y++ {
+			for _, index := range indexes[y] {
+				if index >= 0 && !seen[index] {
+					seen[index] = true
+					bandColors = append(bandColors, index)
+				}
+			}
+		}
+		for i, index := range bandColors {
+			if i > 0 {
+				escape.WriteString("$") // back to the beginning of the band
+			}
+			fmt.Fprintf(&escape, "#%d", index)
+			var sixels []byte
+			for x := range width {
+				bits := 0
+				for dy := range 6 {
+					if top+dy < height && indexes[top+dy][x] == index {
+						bits |= 1 << dy
+					}
+				}
+				sixels = append(sixels, byte(63+bits))
+			}
+			escape.WriteString(runLengthEncode(sixels))
+		}
+		escape.WriteString("-") // next band
+	}
+	escape.WriteString("\u001B\\")
+	return escape.String()
+}
+
+// runLengthEncode compresses the repeated sixels ("!<count><sixel>").
+func runLengthEncode(sixels []byte) string {
+	var encoded strings.Builder
+	for i := 0; i < len(sixels); {
+		j := i
+		for j < len(sixels) && sixels[j] == sixels[i] {
+			j++
+		}
+		if j-i > 3 {
+			fmt.Fprintf(&encoded, "!%d%c", j-i, sixels[i])
+		} else {
+			encoded.WriteString(strings.Repeat(string(sixels[i]), j-i))
+		}
+		i = j
+	}
+	return encoded.String()
+}
+
+// color256 returns the color of an index (16 to 255) of the 256 colors palette.
+func color256(index int) color.NRGBA {
+	if index >= 232 {
+		level := uint8(8 + 10*(index-232))
+		return color.NRGBA{level, level, level, 255}
+	}
+	index -= 16
+	return color.NRGBA{uint8(cubeLevels[index/36]), uint8(cubeLevels[index/6%6]), uint8(cubeLevels[index%6]), 255}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestRunLengthEncode(t *testing.T) {
+	if actual := runLengthEncode([]byte("???~~~~~~@")); actual != "???!6~@" {
+		t.Errorf("Expected '???!6~@', got '%s'", actual)
+	}
+}
+
+func TestColor256(t *testing.T) {
+	for _, index := range []int{16, 21, 196, 231, 232, 244, 255} {
+		if actual := color256Index(color256(index)); actual != index {
+			t.Errorf("color256Index(color256(%d)) = %d", index, actual)
+		}
+	}
+}
+
+func TestSixelImage(t *testing.T) {
+	// 2x2 pixels: red on the top row, transparent on the bottom row
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.NRGBA{255, 0, 0, 255})
+	img.Set(1, 0, color.NRGBA{255, 0, 0, 255})
+	expected := "\u001BP0;1;0q\"1;1;2;2#196;2;100;0;0#196@@-\u001B\\"
+	if actual := sixelImage(img, 2, 2); actual != expected {
+		t.Errorf("Expected %q, got %q", expected, actual)
+	}
+}
+
+func TestKittyImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 200, 200))
+	for y := range 200 {
+		for x := range 200 {
+			// noise, so that the PNG needs more than one chunk
+			img.Set(x, y, color.NRGBA{uint8(x * y), uint8(x + y*7), uint8(x ^ y), 255})
+		}
+	}
+	escape, err := kittyImage(img, 10, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(escape, "\u001B_Ga=T,f=100,i=7101,c=10,r=5,C=1,q=2,m=1;") {
+		t.Errorf("Unexpected first chunk: %.60q", escape)
+	}
+	if !strings.Contains(escape, "\u001B_Gm=0;") || !strings.HasSuffix(escape, "\u001B\\") {
+		t.Errorf("Unexpected last chunk")
+	}
+}
+
+func TestImageLogoDraw(t *testing.T) {
+	logo := &imageLogo{width: 3, height: 2, escape: "IMAGE"}
+	if lines := logo.placeholder(); len(lines) != 2 || lines[0] != "   " {
+		t.Errorf("Unexpected placeholder: %q", lines)
+	}
+	// The logo starts at the 2nd of 5 lines: the cursor goes up 4 lines.
+	if actual := logo.draw(1, 0, 5); actual != "\u001B7\u001B[4A\rIMAGE\u001B8" {
+		t.Errorf("Unexpected escape sequence: %q", actual)
+	}
+	// On the right of the information
+	if actual := logo.draw(1, 40, 5); actual != "\u001B7\u001B[4A\r\u001B[40CIMAGE\u001B8" {
+		t.Errorf("Unexpected escape sequence: %q", actual)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"minfo/pkg/sysinfo"
+)
+
+const testLogo = `
+lines:
+  - text: "##"
+    color: "#61BB46"
+    color256: "28"
+    color16: "32"
+  - color16: "33"
+    spans:
+      - text: "ab"
+        color256: "220"
+      - text: "  "
+      - text: "cd"
+        color: "#E03A3E"
+`
+
+func TestParseLogo(t *testing.T) {
+	tests := []struct {
+		level    int
+		expected []string
+	}{
+		{colorLevelTrue, []string{
+			"\u001B[38;2;97;187;70m##\u001B[0m",
+			"\u001B[38;5;220mab\u001B[33m  \u001B[38;2;224;58;62mcd\u001B[0m",
+		}},
+		{colorLevel256, []string{
+			"\u001B[38;5;28m##\u001B[0m",
+			"\u001B[38;5;220mab\u001B[33m  \u001B[33mcd\u001B[0m",
+		}},
+		{colorLevel16, []string{
+			"\u001B[32m##\u001B[0m",
+			"\u001B[33mab\u001B[33m  \u001B[33mcd\u001B[0m",
+		}},
+		{colorLevelNone, []string{"##", "ab  cd"}},
+	}
+	for _, test := range tests {
+		actual, err := parseLogo([]byte(testLogo), test.level)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !slices.Equal(actual, test.expected) {
+			t.Errorf("Level %d: expected %q, got %q", test.level, test.expected, actual)
+		}
+	}
+}
+
+func TestParseLegacyLogo(t *testing.T) {
+	data := "// comment\n\u001B[00;32m  ##\n\n\u001B[00;33m####\n"
+	actual, err := parseLogo([]byte(data), colorLevelNone)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"\u001B[00;32m  ##", "\u001B[00;33m####"}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("Expected %q, got %q", expected, actual)
+	}
+}
+
+func TestParseInvalidLogo(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"lines:\n  - text: a\n    color: green\n",
+		"lines:\n  - text: a\n    color256: \"300\"\n",
+		"lines:\n  - text: a\n    spans:\n      - text: b\n",
+	} {
+		if _, err := parseLogo([]byte(data), colorLevelTrue); err == nil {
+			t.Errorf("Expected an error for logo %q", data)
+		}
+	}
+}
+
+func TestLoadBuiltinLogo(t *testing.T) {
+	names := builtinLogoNames()
+	if !slices.Contains(names, "apple") || !slices.Contains(names, "apple-nocolor") ||
+		!slices.Contains(names, "apple-256colors") || !slices.Contains(names, "apple-16colors") {
+		t.Fatalf("Expected the apple logos to be embedded, got %v", names)
+	}
+	for _, name := range names {
+		for _, logo := range []string{name, builtinLogoPrefix + name} {
+			if logoLines, err := loadLogo(logo, colorLevelTrue); err != nil || len(logoLines) == 0 {
+				t.Errorf("Logo %s: unexpected error: %v", logo, err)
+			}
+		}
+	}
+	if _, err := loadLogo(builtinLogoPrefix+"foo", colorLevelTrue); err == nil {
+		t.Errorf("Expected an error for an unknown built-in logo")
+	}
+	if _, err := loadLogo("./apple", colorLevelTrue); err == nil {
+		t.Errorf("Expected ./apple to be read as a (missing) file")
+	}
+	// Path of a logo formerly installed with minfo
+	if _, err := loadLogo("/opt/homebrew/share/minfo/apple-256colors", colorLevel256); err != nil {
+		t.Errorf("Expected the apple-256colors logo to be built-in, got %v", err)
+	}
+}
+
+func TestAutoLogo(t *testing.T) {
+	defer func(previous string) { goos = previous }(goos)
+	tests := []struct {
+		goos     string
+		hostInfo sysinfo.Info
+		expected string
+	}{
+		{"darwin", sysinfo.Info{CachedInfo: sysinfo.CachedInfo{Model: &sysinfo.Model{Name: "MacBook Pro"}}}, "macbook"},
+		{"darwin", sysinfo.Info{CachedInfo: sysinfo.CachedInfo{Model: &sysinfo.Model{Name: "Mac mini"}}}, "mac-mini"},
+		{"darwin", sysinfo.Info{CachedInfo: sysinfo.CachedInfo{Model: &sysinfo.Model{Name: "Mac Pro"}}}, "apple"},
+		{"darwin", sysinfo.Info{}, "apple"},
+		{"linux", sysinfo.Info{Os: &sysinfo.OsInfo{Id: "fedora"}}, "fedora"},
+		{"linux", sysinfo.Info{Os: &sysinfo.OsInfo{Id: "linuxmint", IdLike: []string{"ubuntu", "debian"}}}, "ubuntu"},
+		{"linux", sysinfo.Info{Os: &sysinfo.OsInfo{Id: "gentoo"}}, "generic"},
+		{"linux", sysinfo.Info{}, "generic"},
+	}
+	for _, test := range tests {
+		goos = test.goos
+		actual := autoLogo(&test.hostInfo)
+		if actual != test.expected {
+			t.Errorf("Expected logo '%s', got '%s'", test.expected, actual)
+		}
+		if !slices.Contains(builtinLogoNames(), actual) {
+			t.Errorf("Logo '%s' is not a built-in logo", actual)
+		}
+	}
+}
+
+func TestRenderBackground(t *testing.T) {
+	data := `
+lines:
+  - spans:
+      - text: "▀"
+        color256: "196"
+        background256: "21"
+      - text: "▀"
+        color256: "196"
+      - text: " "
+`
+	actual, err := parseLogo([]byte(data), colorLevel256)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The background is reset before the second span
+	expected := []string{"\u001B[38;5;196m\u001B[48;5;21m▀\u001B[0m\u001B[38;5;196m▀\u001B[0m "}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("Expected %q, got %q", expected, actual)
+	}
+}
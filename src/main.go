@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"slices"
+
+	"minfo/pkg/sysinfo"
 )
 
 func main() {
@@ -18,6 +22,20 @@ func main() {
 	}
 	cmdLine.controlCmdLineParams()
 
+	if cmdLine.Command == commandLogo {
+		var err error
+		switch cmdLine.Subcommand {
+		case subcommandList:
+			err = listLogos(os.Stdout)
+		case subcommandConvert:
+			err = convertLogo(os.Stdout, cmdLine.Args[0], cmdLine.width(), cmdLine.charset())
+		}
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return
+	}
+
 	/* ---------- Load and check configuration ---------- */
 	if err := loadAndCheckConfig(cmdLine.ConfigFilePath); err != nil {
 		log.Fatalf("Error loading config: %v", err)
@@ -31,158 +49,147 @@ func main() {
 	if cmdLine.Logo != nil {
 		config.Logo = cmdLine.Logo
 	}
+	if cmdLine.ImageLogo != "" {
+		if config.ImageLogo == nil {
+			config.ImageLogo = &ImageLogoConfig{}
+		}
+		config.ImageLogo.Path = cmdLine.ImageLogo
+	}
 	if cmdLine.DisplayNerdSymbols != nil {
 		config.DisplayNerdSymbols = cmdLine.DisplayNerdSymbols
 	}
-
-	/* ---------- Deal with cache ---------- */
-	// We cache some data which are not going to change:
-	// - computer model, CPU, GPU, memory, and serial number.
-	// If the cache file does not exist yet, we fetch all the data
-	// that can be cached (even if not requested by the suer), and write it,
-	// except if explicitly requested by the user to not use the cache.
-	if *config.Cache {
-		if err := readCacheFile(*config.CacheFilePath, &hostInfo); err != nil {
-			if !errors.Is(err, os.ErrNotExist) && err != errEmptyCache {
-				log.Fatalf("Error reading cache file: %v", err)
-			}
-			// no cache file (or empty) --> Must populate it, i.e. like '-r' was passed.
-			cmdLine.RefreshCache = true
-		} else if !cachedItemsComplete(config.Items, &hostInfo) {
-			cmdLine.RefreshCache = true
-		}
+	if cmdLine.Timeout != nil {
+		config.Timeout = cmdLine.Timeout
 	}
-	// User requested to refresh the cache, or cache file does not exist yet.
-	if cmdLine.RefreshCache {
-		if err := populateCache(*config.CacheFilePath); err != nil {
-			log.Fatalf("Error while refreshing cache: %v", err)
-		}
+	if cmdLine.Format != nil {
+		config.Format = cmdLine.Format
 	}
-
-	/* ---------- Prepare the tasks to execute ---------- */
-	// the functions to execute to fetch the requested information
-	tasks := []func(){}
-
-	// Track which spDataType we will need to fetch from system_profiler
-	spDataTypes := map[string]bool{}
-	writeWeatherCache := true // Do we need to write the weather cache file?
-
-	// First thing first: is the fetchWeather func will need to fetch the public IP ?
-	weatherFetchPublicIP := false
-	if slices.Contains(config.Items, "weather") {
-		if config.Weather.Latitude == nil && config.Weather.LocationNameEn == nil {
-			weatherFetchPublicIP = true
-		}
+	opts := sysinfo.Options{
+		Timeout:          *config.Timeout,
+		ItemTimeouts:     config.ItemTimeouts,
+		Cache:            *config.Cache,
+		CacheFile:        *config.CacheFilePath,
+		RefreshCache:     cmdLine.RefreshCache,
+		Weather:          config.Weather.weatherOptions(),
+		Volumes:          config.Volumes.volumeFilter(),
+		WeatherCacheFile: weatherCacheFile,
 	}
-	for _, requestedItem := range config.Items {
-		item := availableItems[requestedItem]
-
-		// system_profiler data
-		if item.SPDataType != nil {
-			if item.IsCached && *config.Cache {
-				if _, ok := spDataTypes[*item.SPDataType]; !ok {
-					spDataTypes[*item.SPDataType] = false
-				}
-			} else {
-				// Either the spDataType is not cached yet in the map,
-				// or it is in the map but set to false,
-				// in which case we set it to true.
-				_, ok := spDataTypes[*item.SPDataType]
-				if !ok || !spDataTypes[*item.SPDataType] {
-					spDataTypes[*item.SPDataType] = true
-				}
-			}
-		} else if item.Func != nil {
-			// other data, each fetched by its own function.
-			var fetch bool
-			if item.Title == "Weather" {
-				// We have a cache for the weather (default: 15 min)
-				if cmdLine.RefreshCache {
-					fetch = true // Specifically requested to refresh the cache
-				} else if isOlder, err := isFileOlderThan(weatherCacheFile, weatherCacheDuration); err != nil || isOlder {
-					fetch = true // either file > 15 min, or error.
-				} else {
-					tmpInfo := info{}
-					if err := readCacheFile(weatherCacheFile, &tmpInfo); err != nil || tmpInfo.Weather == nil {
-						fetch = true // file exists but empty or error
-					} else {
-						hostInfo.Weather = tmpInfo.Weather
-						writeWeatherCache = false // for later, no need to refresh the cache
-					}
-				}
-			} else if item.Title == "Public IP" {
-				fetch = !weatherFetchPublicIP
-			} else {
-				// for all other items, just fetch the data
-				fetch = true
-			}
-
-			if fetch {
-				nf := item.Func
-				tasks = append(tasks, func() { (*nf).Func(&hostInfo) })
-			}
+	// When recording or replaying the commands' output, we want
+	// all the commands to be run, so we do not use the cache.
+	if cmdLine.RecordDir != "" {
+		opts.Runner = sysinfo.RecordRunner{Dir: cmdLine.RecordDir, Runner: sysinfo.ExecRunner{}}
+		opts.Cache = false
+	} else if cmdLine.ReplayDir != "" {
+		opts.Runner = sysinfo.ReplayRunner{Dir: cmdLine.ReplayDir}
+		goos = "darwin" // Only macOS commands can be recorded.
+		opts.Cache = false
+	}
+	opts.GOOS = goos
+	config.Items = supportedItems(config.Items)
+	opts.Items = config.Items
+	// The automatic logo is chosen from the model and the operating system,
+	// and the header shows the user and the hostname: they are fetched
+	// even if they are not displayed.
+	if cmdLine.Command != commandServe && cmdLine.Output == outputText {
+		var extraItems []string
+		if *config.DisplayLogo && *config.Logo == logoAuto {
+			extraItems = append(extraItems, autoLogoItems...)
+		}
+		if config.Layout.Header {
+			extraItems = append(extraItems, headerItems...)
+		}
+		if len(extraItems) > 0 {
+			opts.Items = uniqueStrings(append(slices.Clone(config.Items), supportedItems(extraItems)...))
 		}
 	}
 
-	// Do we need to run system_profiler ?
-	var spErr error
-	for _, v := range spDataTypes {
-		if v {
-			tasks = append(tasks,
-				func() { spErr = fetchSystemProfiler(&hostInfo, config.Items, spDataTypes, *config.Cache) },
-			)
-			break
+	if cmdLine.Command == commandServe {
+		if cmdLine.Listen != "" {
+			config.Serve.Listen = cmdLine.Listen
 		}
+		if err := runServer(opts, config.Serve); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return
 	}
 
-	/* ---------- Execute tasks ---------- */
-	for _, task := range tasks {
-		task()
+	if cmdLine.Watch != nil {
+		if err := watch(opts, *cmdLine.Watch, cmdLine); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return
 	}
 
-	if spErr != nil {
-		log.Fatalf("Error fetching system profiler: %v", spErr)
+	/* ---------- Fetch information ---------- */
+	hostInfo, fetchErrors, err := collect(context.Background(), opts)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
 	}
-
-	if writeWeatherCache {
-		tmpInfo := info{Weather: hostInfo.Weather}
-		if err := writeCacheFile(weatherCacheFile, &tmpInfo); err != nil {
-			log.Fatalf("Error writing weather cache: %v", err)
+	if cmdLine.Strict {
+		for _, requestedItem := range config.Items {
+			if err, ok := fetchErrors[requestedItem]; ok {
+				log.Fatalf("Error fetching %v", err)
+			}
 		}
 	}
 
 	/* ---------- Display information ---------- */
-	if cmdLine.Json {
-		// We have read information from the cache, so we might
-		// have some information that was not requested by the user.
-		// We need to filter them out.
-
-		for itemName, item := range availableItems {
-			if slices.Contains(config.Items, itemName) || !item.IsCached {
-				continue
-			}
-			switch itemName {
-			case "cpu":
-				hostInfo.Cpu = nil
-			case "gpu":
-				hostInfo.GpuCores = nil
-			case "model":
-				hostInfo.Model = nil
-			case "memory":
-				hostInfo.Memory = nil
-			case "serial_number":
-				hostInfo.SerialNumber = nil
-			}
-		}
-
-		jsonData, err := json.MarshalIndent(hostInfo, "", "  ")
+	switch cmdLine.Output {
+	case outputJson:
+		jsonData, err := marshalJSON(hostInfo, fetchErrors)
 		if err != nil {
 			log.Fatalf("Error marshalling JSON: %v", err)
 		}
 		fmt.Println(string(jsonData))
-	} else {
-		if err := printInfo(&hostInfo); err != nil {
+	case outputPrometheus:
+		if err := sysinfo.WriteMetrics(os.Stdout, hostInfo, fetchErrors); err != nil {
+			log.Fatalf("Error writing metrics: %v", err)
+		}
+	default:
+		output, err := renderInfo(hostInfo, fetchErrors)
+		if err != nil {
 			log.Fatalf("Error printing info: %v", err)
 		}
+		fmt.Print(output)
 	}
 }
+
+// jsonOutput is the information in the JSON output.
+// The items which could not be fetched are listed in "errors", and the
+// status of the checked items (see thresholds.go) in "status".
+type jsonOutput struct {
+	*sysinfo.Info
+	Errors sysinfo.Errors    `json:"errors,omitempty"`
+	Status map[string]string `json:"status,omitempty"`
+}
+
+func newJSONOutput(hostInfo *sysinfo.Info, fetchErrors sysinfo.Errors) jsonOutput {
+	return jsonOutput{hostInfo, fetchErrors, itemStatuses(hostInfo)}
+}
+
+// marshalJSON returns the information as indented JSON (see jsonOutput).
+func marshalJSON(hostInfo *sysinfo.Info, fetchErrors sysinfo.Errors) ([]byte, error) {
+	return json.MarshalIndent(newJSONOutput(hostInfo, fetchErrors), "", "  ")
+}
+
+// collect collects the information with sysinfo.Collect.
+// The items which could not be fetched are returned in fetchErrors,
+// so that they can be displayed as such.
+func collect(ctx context.Context, opts sysinfo.Options) (*sysinfo.Info, sysinfo.Errors, error) {
+	hostInfo, err := sysinfo.Collect(ctx, opts)
+	fetchErrors := sysinfo.Errors{}
+	if err != nil && !errors.As(err, &fetchErrors) {
+		return nil, nil, err
+	}
+	return hostInfo, fetchErrors, nil
+}
+
+// mergeCollection updates the information (and the errors) of the given items
+// with a newer collection of these items.
+func mergeCollection(hostInfo *sysinfo.Info, fetchErrors sysinfo.Errors, newInfo *sysinfo.Info, newErrors sysinfo.Errors, items []string) {
+	hostInfo.CopyItems(newInfo, items)
+	maps.DeleteFunc(fetchErrors, func(item string, _ *sysinfo.ItemError) bool {
+		return slices.Contains(items, item)
+	})
+	maps.Copy(fetchErrors, newErrors)
+}
@@ -0,0 +1,73 @@
+package sysinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+var errEmptyCache = errors.New("cache file is empty")
+
+func readCacheFile(cacheFilePath string, out *Info) (err error) {
+	var fileInfo os.FileInfo
+	if fileInfo, err = os.Stat(cacheFilePath); err != nil {
+		return
+	}
+	if fileInfo.Size() == 0 {
+		return errEmptyCache
+	}
+	var cacheData []byte
+	if cacheData, err = os.ReadFile(cacheFilePath); err != nil {
+		return
+	}
+	err = json.Unmarshal(cacheData, out)
+	return
+}
+
+func writeCacheFile(cacheFilePath string, hostInfo *Info) (err error) {
+	dirPath := filepath.Dir(cacheFilePath)
+	if err = ensureDirExists(dirPath); err != nil {
+		return
+	}
+	var jsonData []byte
+	if jsonData, err = json.MarshalIndent(*hostInfo, "", "  "); err != nil {
+		return
+	}
+	err = os.WriteFile(cacheFilePath, jsonData, 0644)
+	return
+}
+
+// populateCache fetches (into hostInfo) all the items that can be cached,
+// and writes the cache file.
+// It returns the errors of the items that could not be fetched.
+func populateCache(ctx context.Context, opts *Options, hostInfo *Info) (Errors, error) {
+	var toFetch []*collector
+	for _, name := range Items() {
+		if c := collectors[name]; c.cached && c.supports(opts.GOOS) {
+			toFetch = append(toFetch, c)
+		}
+	}
+
+	src := newSources(ctx, opts, toFetch)
+	errs := fetchCollectors(ctx, src, toFetch, hostInfo)
+
+	if err := writeCacheFile(opts.CacheFile, hostInfo); err != nil {
+		return errs, err
+	}
+	return errs, nil
+}
+
+func cachedItemsComplete(items []string, info *Info) bool {
+	for _, itemName := range items {
+		c, ok := collectors[itemName]
+		if !ok || !c.cached {
+			continue
+		}
+		if !isFieldSet(c, info) {
+			return false
+		}
+	}
+	return true
+}
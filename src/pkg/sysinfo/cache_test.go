@@ -1,4 +1,4 @@
-package main
+package sysinfo
 
 import (
 	"errors"
@@ -16,7 +16,7 @@ func TestReadCacheFileEmpty(t *testing.T) {
 		t.Fatalf("failed to create temp file: %v", err)
 	}
 
-	var out info
+	var out Info
 	err := readCacheFile(tempFile, &out)
 	if !errors.Is(err, errEmptyCache) {
 		t.Fatalf("expected errEmptyCache, got %v", err)
@@ -27,9 +27,8 @@ func TestWriteAndReadCacheFileRoundTrip(t *testing.T) {
 	t.Parallel()
 
 	tempFile := filepath.Join(t.TempDir(), "cache.json")
-	gpuCores := 10
-	expected := info{
-		cachedInfo: cachedInfo{
+	expected := Info{
+		CachedInfo: CachedInfo{
 			Model: &Model{
 				Name:    "MacBook Pro",
 				SubName: "16-inch",
@@ -42,7 +41,7 @@ func TestWriteAndReadCacheFileRoundTrip(t *testing.T) {
 				PerformanceCores: 12,
 				EfficiencyCores:  4,
 			},
-			GpuCores:     &gpuCores,
+			Gpus:         []Gpu{{Vendor: "Apple", Model: "Apple M4 Max", Cores: 40, Metal: "Metal 3"}},
 			Memory:       &Memory{Amount: 64, Unit: "GB", MemType: "LPDDR5"},
 			SerialNumber: func() *string { s := "SERIAL"; return &s }(),
 		},
@@ -52,7 +51,7 @@ func TestWriteAndReadCacheFileRoundTrip(t *testing.T) {
 		t.Fatalf("writeCacheFile failed: %v", err)
 	}
 
-	var actual info
+	var actual Info
 	if err := readCacheFile(tempFile, &actual); err != nil {
 		t.Fatalf("readCacheFile failed: %v", err)
 	}
@@ -63,8 +62,8 @@ func TestWriteAndReadCacheFileRoundTrip(t *testing.T) {
 	if actual.Model == nil || actual.Model.Name != expected.Model.Name {
 		t.Fatalf("Model mismatch: got %+v", actual.Model)
 	}
-	if actual.GpuCores == nil || *actual.GpuCores != *expected.GpuCores {
-		t.Fatalf("GPU cores mismatch: got %v", actual.GpuCores)
+	if len(actual.Gpus) != 1 || actual.Gpus[0] != expected.Gpus[0] {
+		t.Fatalf("GPUs mismatch: got %+v", actual.Gpus)
 	}
 	if actual.Memory == nil || actual.Memory.Amount != expected.Memory.Amount {
 		t.Fatalf("Memory mismatch: got %+v", actual.Memory)
@@ -74,6 +73,23 @@ func TestWriteAndReadCacheFileRoundTrip(t *testing.T) {
 	}
 }
 
+// A host without GPU (Ex. headless Linux) is cached as such, and not fetched again.
+func TestWriteAndReadCacheFileNoGpu(t *testing.T) {
+	t.Parallel()
+
+	tempFile := filepath.Join(t.TempDir(), "cache.json")
+	if err := writeCacheFile(tempFile, &Info{CachedInfo: CachedInfo{Gpus: []Gpu{}}}); err != nil {
+		t.Fatalf("writeCacheFile failed: %v", err)
+	}
+	var actual Info
+	if err := readCacheFile(tempFile, &actual); err != nil {
+		t.Fatalf("readCacheFile failed: %v", err)
+	}
+	if !cachedItemsComplete([]string{"gpu"}, &actual) {
+		t.Errorf("Expected the cached GPUs to be complete, got %+v", actual.Gpus)
+	}
+}
+
 func TestIsFileOlderThan(t *testing.T) {
 	t.Parallel()
 
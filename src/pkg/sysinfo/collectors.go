@@ -0,0 +1,291 @@
+package sysinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*
+This file contains the collectors, i.e. the registry of all the
+items that can be fetched.
+To add a new item, define its collector in items.go and register it there.
+*/
+
+var (
+	ErrUnsupported = errors.New("not supported on this operating system")
+	ErrTimedOut    = errors.New("timed out")
+)
+
+// ItemError is the error of an item which could not be fetched.
+type ItemError struct {
+	Item string
+	Err  error
+}
+
+func (e *ItemError) Error() string { return fmt.Sprintf("%s: %v", e.Item, e.Err) }
+func (e *ItemError) Unwrap() error { return e.Err }
+
+// Reason returns the reason why the item could not be fetched, as displayed to the user.
+func (e *ItemError) Reason() string { return e.Err.Error() }
+
+// Errors holds the errors of the items which could not be fetched, by item name.
+// In JSON, it is an object mapping each item to the reason of its error.
+type Errors map[string]*ItemError
+
+func (errs Errors) Error() string {
+	var msgs []string
+	for _, name := range slices.Sorted(maps.Keys(errs)) {
+		msgs = append(msgs, errs[name].Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (errs Errors) MarshalJSON() ([]byte, error) {
+	reasons := map[string]string{}
+	for name, err := range errs {
+		reasons[name] = err.Reason()
+	}
+	return json.Marshal(reasons)
+}
+
+// collector fetches the information of an item.
+// Fetching can be done:
+//   - by fetch, on all operating systems.
+//   - by fetchSP on macOS, from the output of system_profiler (see spDataType).
+//   - by fetchDarwin on macOS, from other commands (Ex. vm_stat).
+//   - by fetchLinux on Linux.
+type collector struct {
+	// Name of the item, as used in the configuration file. Ex. "public_ip"
+	name string
+	// Whether the information is stored in the cache file.
+	cached      bool
+	spDataType  string
+	fetch       func(ctx context.Context, src *sources, hostInfo *Info) error
+	fetchSP     func(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error
+	fetchDarwin func(ctx context.Context, src *sources, hostInfo *Info) error
+	fetchLinux  func(ctx context.Context, src *sources, hostInfo *Info) error
+	// field returns a pointer to the field of hostInfo holding the information,
+	// i.e. the field that appears in the JSON output and in the cache file.
+	field func(hostInfo *Info) any
+}
+
+// supports returns whether the information can be fetched on the given OS (Ex. "linux").
+func (c *collector) supports(goos string) bool {
+	switch {
+	case c.fetch != nil:
+		return true
+	case goos == "linux":
+		return c.fetchLinux != nil
+	default:
+		return c.fetchSP != nil || c.fetchDarwin != nil
+	}
+}
+
+// Fetch the information and store it in hostInfo.
+// Fetching must stop (and the commands be killed) when ctx is done.
+func (c *collector) fetchInfo(ctx context.Context, src *sources, hostInfo *Info) error {
+	switch {
+	case c.fetch != nil:
+		return c.fetch(ctx, src, hostInfo)
+	case !c.supports(src.opts.GOOS):
+		return ErrUnsupported
+	case src.opts.GOOS == "linux":
+		return c.fetchLinux(ctx, src, hostInfo)
+	case c.fetchDarwin != nil:
+		return c.fetchDarwin(ctx, src, hostInfo)
+	default:
+		spInfo, err := src.systemProfiler()
+		if err != nil {
+			return err
+		}
+		return c.fetchSP(ctx, src, spInfo, hostInfo)
+	}
+}
+
+/* ---------- Registry ---------- */
+
+// All available items we can fetch, by name.
+var collectors = map[string]*collector{}
+
+func registerCollector(c *collector) {
+	collectors[c.name] = c
+}
+
+// Items returns the (sorted) names of all the items that can be collected.
+func Items() []string {
+	var names []string
+	for name := range collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Supported returns whether the item can be fetched on the given OS (Ex. "linux"),
+// so that the same list of items can be used on every platform.
+func Supported(item, goos string) bool {
+	c, ok := collectors[item]
+	return ok && c.supports(goos)
+}
+
+// IsCached returns whether the information of the item is stored in the cache file.
+func IsCached(item string) bool {
+	c, ok := collectors[item]
+	return ok && c.cached
+}
+
+// isFieldSet returns true if the information of the item is set in hostInfo.
+func isFieldSet(c *collector, hostInfo *Info) bool {
+	return !reflect.ValueOf(c.field(hostInfo)).Elem().IsZero()
+}
+
+// clearField removes the information of the item from hostInfo.
+func clearField(c *collector, hostInfo *Info) {
+	field := reflect.ValueOf(c.field(hostInfo)).Elem()
+	field.Set(reflect.Zero(field.Type()))
+}
+
+/* ---------- Shared sources of information ---------- */
+
+// sources holds the information shared by several collectors,
+// so that it is fetched only once (Ex. system_profiler output),
+// whichever collector asks for it first.
+// Shared information is fetched with the overall deadline (ctx),
+// not with the timeout of the collector which asked for it.
+type sources struct {
+	ctx         context.Context
+	opts        *Options
+	spDataTypes []string
+
+	spOnce sync.Once
+	spInfo *systemProfilerInfo
+	spErr  error
+
+	spDisplaysOnce sync.Once
+	spDisplays     *systemProfilerInfo
+	spDisplaysErr  error
+
+	publicIpOnce sync.Once
+	publicIpInfo *PublicIpInfo
+	publicIpErr  error
+}
+
+// newSources prepares the sources needed by the given collectors.
+func newSources(ctx context.Context, opts *Options, toFetch []*collector) *sources {
+	src := &sources{ctx: ctx, opts: opts}
+	for _, c := range toFetch {
+		if c.spDataType != "" && !slices.Contains(src.spDataTypes, c.spDataType) {
+			src.spDataTypes = append(src.spDataTypes, c.spDataType)
+		}
+	}
+	sort.Strings(src.spDataTypes)
+	return src
+}
+
+// systemProfiler runs system_profiler (only once) with all the SPDataTypes
+// needed by the collectors.
+func (src *sources) systemProfiler() (*systemProfilerInfo, error) {
+	src.spOnce.Do(func() {
+		src.spInfo, src.spErr = runSystemProfiler(src.ctx, src.opts.Runner, src.spDataTypes)
+	})
+	return src.spInfo, src.spErr
+}
+
+// spDisplaysInfo returns spInfo, unless it has no SPDisplaysDataType information at all
+// (not even the GPUs): for some unknown reason, system_profiler sometimes returns none,
+// and it is then called again (only once, for both the gpu and the display items).
+func (src *sources) spDisplaysInfo(spInfo *systemProfilerInfo) (*systemProfilerInfo, error) {
+	if len(spInfo.Displays) > 0 {
+		return spInfo, nil
+	}
+	src.spDisplaysOnce.Do(func() {
+		src.spDisplays, src.spDisplaysErr = runSystemProfiler(src.ctx, src.opts.Runner, []string{SPDisplaysDataType})
+	})
+	return src.spDisplays, src.spDisplaysErr
+}
+
+// publicIp looks up the public IP (only once), used by both
+// the public_ip and the weather items.
+func (src *sources) publicIp() (*PublicIpInfo, error) {
+	src.publicIpOnce.Do(func() {
+		src.publicIpInfo, src.publicIpErr = lookupPublicIp(src.ctx)
+	})
+	return src.publicIpInfo, src.publicIpErr
+}
+
+/* ---------- Concurrent fetching ---------- */
+
+// fetchCollectors runs all the collectors concurrently and stores
+// the fetched information in hostInfo.
+// Each collector has its own timeout (see Options.ItemTimeouts), bounded by
+// the overall deadline of ctx. The collectors that did not finish in
+// time are given up and reported with ErrTimedOut.
+func fetchCollectors(ctx context.Context, src *sources, toFetch []*collector, hostInfo *Info) Errors {
+	type result struct {
+		scratch Info
+		err     error
+	}
+	results := make([]result, len(toFetch))
+
+	var wg sync.WaitGroup
+	for i, c := range toFetch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			itemCtx, cancel := context.WithTimeout(ctx, src.opts.itemTimeout(c.name))
+			// Canceling the context kills the commands still running.
+			defer cancel()
+
+			// Each collector fetches into its own scratch info,
+			// so a collector which is given up cannot change hostInfo.
+			done := make(chan result, 1)
+			go func() {
+				var r result
+				r.err = c.fetchInfo(itemCtx, src, &r.scratch)
+				done <- r
+			}()
+			select {
+			case results[i] = <-done:
+				if results[i].err != nil && itemCtx.Err() != nil {
+					results[i].err = ErrTimedOut
+				}
+			case <-itemCtx.Done():
+				results[i].err = ErrTimedOut
+			}
+		}()
+	}
+	wg.Wait()
+
+	errs := Errors{}
+	for i, c := range toFetch {
+		if results[i].err != nil {
+			errs[c.name] = &ItemError{Item: c.name, Err: results[i].err}
+			continue
+		}
+		copyField(c, hostInfo, &results[i].scratch)
+	}
+	return errs
+}
+
+// CopyItems copies the information of the given items from src to hostInfo,
+// Ex. to update some items with a newer collection.
+func (hostInfo *Info) CopyItems(src *Info, items []string) {
+	for _, item := range items {
+		if c, ok := collectors[item]; ok {
+			copyField(c, hostInfo, src)
+		}
+	}
+}
+
+// copyField copies the information of the item from src to dst.
+func copyField(c *collector, dst, src *Info) {
+	reflect.ValueOf(c.field(dst)).Elem().Set(reflect.ValueOf(c.field(src)).Elem())
+}
@@ -0,0 +1,94 @@
+package sysinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDefaultItemsAreRegistered(t *testing.T) {
+	for _, item := range DefaultItems {
+		if _, ok := collectors[item]; !ok {
+			t.Errorf("Default item '%s' has no registered collector", item)
+		}
+	}
+}
+
+func TestIsFieldSetAndClearField(t *testing.T) {
+	serial := "SERIAL"
+	hostInfo := Info{
+		CachedInfo: CachedInfo{SerialNumber: &serial},
+		Hostname:   "host",
+	}
+
+	for _, name := range []string{"serial_number", "hostname"} {
+		c := collectors[name]
+		if !isFieldSet(c, &hostInfo) {
+			t.Fatalf("Expected %s to be set", name)
+		}
+		clearField(c, &hostInfo)
+		if isFieldSet(c, &hostInfo) {
+			t.Fatalf("Expected %s to be cleared", name)
+		}
+	}
+	if hostInfo.SerialNumber != nil || hostInfo.Hostname != "" {
+		t.Fatalf("Fields were not cleared: %+v", hostInfo)
+	}
+}
+
+func TestFetchCollectorsTimeout(t *testing.T) {
+	slow := &collector{
+		name: "hostname",
+		fetch: func(ctx context.Context, _ *sources, hostInfo *Info) error {
+			time.Sleep(time.Second) // does not honor ctx
+			hostInfo.Hostname = "too late"
+			return nil
+		},
+		field: func(hostInfo *Info) any { return &hostInfo.Hostname },
+	}
+	fast := &collector{
+		name: "terminal",
+		fetch: simpleFetch(func(hostInfo *Info) {
+			hostInfo.Terminal = "term"
+		}),
+		field: func(hostInfo *Info) any { return &hostInfo.Terminal },
+	}
+
+	opts := Options{
+		Timeout:      time.Second,
+		ItemTimeouts: map[string]time.Duration{"hostname": 10 * time.Millisecond},
+	}
+	ctx := context.Background()
+	var hostInfo Info
+	errs := fetchCollectors(ctx, newSources(ctx, &opts, nil), []*collector{slow, fast}, &hostInfo)
+
+	if !errors.Is(errs["hostname"], ErrTimedOut) {
+		t.Errorf("Expected hostname to time out, got %v", errs["hostname"])
+	}
+	if _, ok := errs["terminal"]; ok {
+		t.Errorf("Unexpected error for terminal: %v", errs["terminal"])
+	}
+	if hostInfo.Hostname != "" {
+		t.Errorf("Expected hostname not to be set, got '%s'", hostInfo.Hostname)
+	}
+	if hostInfo.Terminal != "term" {
+		t.Errorf("Expected terminal to be 'term', got '%s'", hostInfo.Terminal)
+	}
+}
+
+func TestErrorsMarshalJSON(t *testing.T) {
+	errs := Errors{
+		"weather":  &ItemError{Item: "weather", Err: ErrTimedOut},
+		"software": &ItemError{Item: "software", Err: errors.New("brew failed")},
+	}
+	data, err := json.Marshal(errs)
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	want := `{"software":"brew failed","weather":"timed out"}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
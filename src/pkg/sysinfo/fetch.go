@@ -0,0 +1,800 @@
+package sysinfo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/micromdm/plist"
+)
+
+var (
+	weatherHTTPClient  = &http.Client{Timeout: 3 * time.Second}
+	geoHTTPClient      = &http.Client{Timeout: 2 * time.Second}
+	publicIPHTTPClient = &http.Client{Timeout: 1200 * time.Millisecond}
+)
+
+// Custom UnmarshalJSON to handle both "chip_type" and "cpu_type"
+func (h *HardwareInfo) UnmarshalJSON(data []byte) error {
+	// Parse the input JSON into a temporary map
+	var temp map[string]any
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+
+	// Look for "chip_type" or "cpu_type" and assign to ChipType
+	if val, ok := temp["chip_type"].(string); ok {
+		h.ChipType = val
+	} else if val, ok := temp["cpu_type"].(string); ok {
+		h.ChipType = val
+	}
+	// Assign the rest of the fields
+	h.MachineModel = temp["machine_model"].(string)
+	h.MachineName = temp["machine_name"].(string)
+	if val, ok := temp["model_number"].(string); ok {
+		h.ModelNumber = val
+	}
+	h.NumProc = temp["number_processors"]
+
+	if val, ok := temp["serial_number"].(string); ok {
+		h.SerialNumber = val
+	}
+
+	return nil
+}
+
+// runSystemProfiler calls system_profiler with the needed SPDataType(s)
+// and parses its output.
+func runSystemProfiler(ctx context.Context, runner CommandRunner, spDataTypes []string) (*systemProfilerInfo, error) {
+	var spInfo systemProfilerInfo
+
+	args := append([]string{"-json", "-detailLevel", "basic"}, spDataTypes...)
+	output, err := runner.Run(ctx, "/usr/sbin/system_profiler", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal([]byte(output), &spInfo); err != nil {
+		return nil, err
+	}
+	return &spInfo, nil
+}
+
+/* ---------- Parse the output of system_profiler ---------- */
+
+func spFetchModel(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	if len(spInfo.Hardware) == 0 {
+		return fmt.Errorf("system_profiler returned no hardware information")
+	}
+	// We also have to call ioreg to get all the information about the model
+	hostInfo.Model = &Model{}
+	fetchModelYear(ctx, src.opts.Runner, hostInfo.Model)
+	(*hostInfo.Model).Number = spInfo.Hardware[0].ModelNumber
+	return nil
+}
+
+func spFetchCpu(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	if len(spInfo.Hardware) == 0 {
+		return fmt.Errorf("system_profiler returned no hardware information")
+	}
+	// Note: differences between Apple Silicon and Intel CPUs:
+	// - field called "chip_type" for Apple Silicon
+	// - field called "cpu_type" for Intel
+	// - field called "number_processors" is a string for Apple Silicon
+	// - field called "number_processors" is an int for Intel
+	hostInfo.Cpu = &Cpu{}
+	(*hostInfo.Cpu).Model = spInfo.Hardware[0].ChipType
+	switch v := spInfo.Hardware[0].NumProc.(type) {
+	case string:
+		cpuCoreInfoArr := strings.Split(strings.Split(v, " ")[1], ":")
+		(*hostInfo.Cpu).Cores, _ = strconv.Atoi(cpuCoreInfoArr[0])
+		(*hostInfo.Cpu).PerformanceCores, _ = strconv.Atoi(cpuCoreInfoArr[1])
+		(*hostInfo.Cpu).EfficiencyCores, _ = strconv.Atoi(cpuCoreInfoArr[2])
+	case int:
+		(*hostInfo.Cpu).Cores = int(v)
+	}
+	return nil
+}
+
+func spFetchGpu(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	spInfo, err := src.spDisplaysInfo(spInfo)
+	if err != nil {
+		return err
+	}
+	if len(spInfo.Displays) == 0 {
+		return fmt.Errorf("system_profiler returned no display information")
+	}
+	hostInfo.Gpus = []Gpu{}
+	for _, display := range spInfo.Displays {
+		gpu := Gpu{
+			// Ex. "sppci_vendor_Apple", "Intel", "NVIDIA (0x10de)"
+			Vendor: strings.TrimPrefix(strings.Split(display.Vendor, " (")[0], "sppci_vendor_"),
+			Model:  display.Model,
+			VRAM:   display.VRAM,
+		}
+		if gpu.Vendor == "amd" {
+			gpu.Vendor = "AMD"
+		}
+		if gpu.Model == "" {
+			gpu.Model = display.Name
+		}
+		gpu.Cores, _ = strconv.Atoi(display.NumCores)
+		if gpu.VRAM == "" && display.VRAMShared != "" {
+			gpu.VRAM = display.VRAMShared + " (shared)"
+		}
+		if version, ok := strings.CutPrefix(display.MetalFamily, "spdisplays_metal"); ok {
+			gpu.Metal = strings.TrimSpace("Metal " + version)
+		} else if display.Metal == "spdisplays_supported" {
+			gpu.Metal = "Metal"
+		}
+		hostInfo.Gpus = append(hostInfo.Gpus, gpu)
+	}
+	return nil
+}
+
+func spFetchMemory(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	if len(spInfo.Memory) == 0 {
+		return fmt.Errorf("system_profiler returned no memory information")
+	}
+	hostInfo.Memory = &Memory{}
+	for _, mem := range spInfo.Memory {
+		memMap, _ := mem.(map[string]interface{})
+		// Apple Silicon: a single entry with the total amount of memory.
+		// Intel: one entry per DIMM in "Items".
+		// (We do not rely on the architecture, so that an output
+		// recorded on a Mac can be replayed anywhere)
+		if _, ok := memMap["SPMemoryDataType"]; ok {
+			memUnit := strings.Split(memMap["SPMemoryDataType"].(string), " ")
+			(*hostInfo.Memory).Amount, _ = strconv.Atoi(memUnit[0])
+			(*hostInfo.Memory).Unit = memUnit[1]
+			(*hostInfo.Memory).MemType = memMap["dimm_type"].(string)
+		} else if dimms, ok := memMap["Items"].([]interface{}); ok {
+			for _, item := range dimms {
+				itemMap, _ := item.(map[string]interface{})
+				memUnit := strings.Split(itemMap["dimm_size"].(string), " ")
+				tmp, _ := strconv.Atoi(memUnit[0])
+				(*hostInfo.Memory).Amount += tmp
+				// Unit and MemType are the same for all DIMMs.
+				// Let's only fill it once.
+				if (*hostInfo.Memory).Unit == "" {
+					(*hostInfo.Memory).Unit = memUnit[1]
+					(*hostInfo.Memory).MemType = itemMap["dimm_type"].(string)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func spFetchUser(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	if len(spInfo.Software) == 0 {
+		return fmt.Errorf("system_profiler returned no software information")
+	}
+	re := regexp.MustCompile(`^([\w\s]+)\s\((\w+)\)$`)
+	matches := re.FindStringSubmatch(spInfo.Software[0].UserName)
+
+	hostInfo.User = &UserInfo{}
+	if len(matches) == 3 {
+		(*hostInfo.User).RealName = matches[1]
+		(*hostInfo.User).Login = matches[2]
+	}
+	return nil
+}
+
+func spFetchHostname(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	if len(spInfo.Software) == 0 {
+		return fmt.Errorf("system_profiler returned no software information")
+	}
+	hostInfo.Hostname = spInfo.Software[0].HostName
+	return nil
+}
+
+func spFetchOs(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	if len(spInfo.Software) == 0 {
+		return fmt.Errorf("system_profiler returned no software information")
+	}
+	hostInfo.Os = &OsInfo{}
+	re := regexp.MustCompile(`^(\w+)\s([\d.]+)\s\(([^)]+)\)$`)
+	matches := re.FindStringSubmatch(spInfo.Software[0].OsVersion)
+	if len(matches) == 4 {
+		hostInfo.Os.System = matches[1]        // "macOS"
+		hostInfo.Os.SystemVersion = matches[2] // "15.2"
+		hostInfo.Os.SystemBuild = matches[3]   // "24C101"
+	}
+	kernelInfoArr := strings.Split(spInfo.Software[0].Kernel, " ")
+	hostInfo.Os.KernelType = kernelInfoArr[0]
+	hostInfo.Os.KernelVersion = kernelInfoArr[1]
+	majorOsVersion := strings.Split(hostInfo.Os.SystemVersion, ".")[0]
+	var osFriendlyNameMap = map[string]string{
+		"13": "Ventura",
+		"14": "Sonoma",
+		"15": "Sequoia",
+		"26": "Tahoe",
+	}
+	var ok bool
+
+	if hostInfo.Os.SystemVersionCodeNname, ok = osFriendlyNameMap[majorOsVersion]; !ok {
+		hostInfo.Os.SystemVersionCodeNname = "(Unknown)"
+	}
+	return nil
+}
+
+func spFetchSystemIntegrity(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	if len(spInfo.Software) == 0 {
+		return fmt.Errorf("system_profiler returned no software information")
+	}
+	hostInfo.SystemIntegrity = spInfo.Software[0].SystemIntegrity
+	return nil
+}
+
+func spFetchSerialNumber(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	if len(spInfo.Hardware) == 0 {
+		return fmt.Errorf("system_profiler returned no hardware information")
+	}
+	hostInfo.SerialNumber = &spInfo.Hardware[0].SerialNumber
+	return nil
+}
+
+func spFetchDisk(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	if len(spInfo.Storage) == 0 {
+		return fmt.Errorf("system_profiler returned no storage information")
+	}
+	hostInfo.Disk = &DiskInfo{}
+	for _, hd := range spInfo.Storage {
+		if hd.MountPoint == "/" {
+			hostInfo.Disk.TotalBytes = uint64(hd.SizeByte)
+			hostInfo.Disk.FreeBytes = uint64(hd.FreeSpaceByte)
+			hostInfo.Disk.TotalTB = float32(hd.SizeByte) / 1000000000000
+			hostInfo.Disk.FreeTB = float32(hd.FreeSpaceByte) / 1000000000000
+			hostInfo.Disk.SmartStatus = hd.PhyDrive.SmartStatus
+			break
+		}
+	}
+	return nil
+}
+
+func spFetchBattery(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	if len(spInfo.Power) == 0 {
+		return fmt.Errorf("system_profiler returned no power information")
+	}
+	hostInfo.Battery = &BatteryInfo{}
+	hostInfo.Battery.StatusPercent = spInfo.Power[0].BatteryChargeInfo.StateOfCharge
+	hostInfo.Battery.CapacityPercent, _ = strconv.Atoi(strings.TrimSuffix(spInfo.Power[0].BatteryHealthInfo.MaxCapacity, "%"))
+
+	if spInfo.Power[0].BatteryChargeInfo.IsCharging == "FALSE" {
+		hostInfo.Battery.Charging = false
+	} else {
+		hostInfo.Battery.Charging = true
+	}
+	hostInfo.Battery.Health = spInfo.Power[0].BatteryHealthInfo.Health
+	hostInfo.Battery.CycleCount = spInfo.Power[0].BatteryHealthInfo.CycleCount
+	hostInfo.Battery.FullyCharged = spInfo.Power[0].BatteryChargeInfo.FullyCharged == "TRUE"
+	hostInfo.Battery.AtWarnLevel = spInfo.Power[0].BatteryChargeInfo.AtWarnLevel == "TRUE"
+
+	for _, power := range spInfo.Power {
+		if power.Name != "sppower_ac_charger_information" {
+			continue
+		}
+		adapter := &ACAdapterInfo{Connected: power.ChargerConnected == "TRUE"}
+		switch v := power.ChargerWatts.(type) {
+		case string:
+			adapter.Watts, _ = strconv.Atoi(v)
+		case float64:
+			adapter.Watts = int(v)
+		}
+		hostInfo.Battery.ACAdapter = adapter
+	}
+
+	// system_profiler does not estimate the remaining time, but pmset does.
+	// It is not always available (Ex. "(no estimate)" right after unplugging).
+	output, err := src.opts.Runner.Run(ctx, "/usr/bin/pmset", "-g", "batt")
+	if err == nil {
+		if minutes, ok := parsePmsetRemaining(output); ok {
+			if hostInfo.Battery.Charging {
+				hostInfo.Battery.TimeToFullMinutes = minutes
+			} else {
+				hostInfo.Battery.TimeToEmptyMinutes = minutes
+			}
+		}
+	}
+	return nil
+}
+
+// parsePmsetRemaining returns the remaining time of the output of "pmset -g batt",
+// in minutes. Ex. "-InternalBattery-0 (id=4653155)	94%; discharging; 5:12 remaining present: true"
+func parsePmsetRemaining(output string) (int, bool) {
+	match := regexp.MustCompile(`(\d+):(\d{2}) remaining`).FindStringSubmatch(output)
+	if match == nil {
+		return 0, false
+	}
+	hours, _ := strconv.Atoi(match[1])
+	minutes, _ := strconv.Atoi(match[2])
+	return hours*60 + minutes, true
+}
+
+func spFetchVolumes(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	hostInfo.Volumes = []Volume{}
+	for _, hd := range spInfo.Storage {
+		volume := Volume{
+			Name:        hd.Name,
+			MountPoint:  hd.MountPoint,
+			FileSystem:  hd.FileSystem,
+			TotalBytes:  uint64(hd.SizeByte),
+			FreeBytes:   uint64(hd.FreeSpaceByte),
+			SmartStatus: hd.PhyDrive.SmartStatus,
+		}
+		if !src.opts.Volumes.matches(volume) {
+			continue
+		}
+		if hd.BsdName != "" {
+			volume.Encrypted = fetchVolumeEncryption(ctx, src.opts.Runner, hd.BsdName)
+		}
+		hostInfo.Volumes = append(hostInfo.Volumes, volume)
+	}
+	return nil
+}
+
+// Fetch whether a volume is encrypted (FileVault, or an encrypted APFS volume). CALLED BY spFetchVolumes()
+// system_profiler does not report it, but "diskutil info" does. Returns nil if unknown.
+func fetchVolumeEncryption(ctx context.Context, runner CommandRunner, bsdName string) *bool {
+	output, err := runner.Run(ctx, "/usr/sbin/diskutil", "info", "-plist", bsdName)
+	if err != nil {
+		return nil
+	}
+	var info struct {
+		Encryption bool `plist:"Encryption"`
+		FileVault  bool `plist:"FileVault"`
+	}
+	if err := plist.NewXMLDecoder(strings.NewReader(output)).Decode(&info); err != nil {
+		return nil
+	}
+	encrypted := info.Encryption || info.FileVault
+	return &encrypted
+}
+
+// Connection types of system_profiler, Ex. "spdisplays_displayport"
+var spConnectionTypes = map[string]string{
+	"internal":    "Internal",
+	"displayport": "DisplayPort",
+	"hdmi":        "HDMI",
+	"thunderbolt": "Thunderbolt",
+	"dvi":         "DVI",
+	"vga":         "VGA",
+	"airplay":     "AirPlay",
+}
+
+func spFetchDisplays(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	spInfo, err := src.spDisplaysInfo(spInfo)
+	if err != nil {
+		return err
+	}
+	if len(spInfo.Displays) == 0 {
+		return fmt.Errorf("system_profiler returned no display information")
+	}
+	// A Mac without any display (Ex. a headless Mac mini) has GPUs without displays: not an error.
+	hostInfo.Displays = spDisplays(spInfo)
+	return nil
+}
+
+// spDisplays returns the displays connected to all the GPUs. CALLED BY spFetchDisplays()
+func spDisplays(spInfo *systemProfilerInfo) []Display {
+	re := regexp.MustCompile(`^(\d+)\s*x\s*(\d+)\s*@\s*([\d.]+)Hz$`)
+	var displays []Display
+	for _, gpu := range spInfo.Displays {
+		for _, displayInfo := range gpu.Ndrvs {
+			if displayInfo.Online == "spdisplays_no" {
+				continue
+			}
+			dInfo := Display{Name: displayInfo.Name}
+			tmpArr := strings.Split(displayInfo.Pixels, " x ")
+			dInfo.PixelsWidth, _ = strconv.Atoi(tmpArr[0])
+			if len(tmpArr) == 2 {
+				dInfo.PixelsHeight, _ = strconv.Atoi(tmpArr[1])
+			}
+			matches := re.FindStringSubmatch(displayInfo.Resolution)
+			if len(matches) == 4 {
+				dInfo.ResolutionWidth, _ = strconv.Atoi(matches[1])
+				dInfo.ResolutionHeight, _ = strconv.Atoi(matches[2])
+				dInfo.RefreshRateHz, _ = strconv.ParseFloat(matches[3], 64)
+			}
+			if dInfo.ResolutionWidth > 0 {
+				dInfo.ScaleFactor = float64(dInfo.PixelsWidth) / float64(dInfo.ResolutionWidth)
+			}
+			connection := strings.TrimPrefix(displayInfo.ConnectionType, "spdisplays_")
+			if name, ok := spConnectionTypes[connection]; ok {
+				connection = name
+			}
+			dInfo.Connection = connection
+			dInfo.BuiltIn = connection == "Internal" || strings.Contains(displayInfo.DisplayType, "built-in")
+			dInfo.Main = displayInfo.Main == "spdisplays_yes"
+			dInfo.Mirrored = displayInfo.Mirror == "spdisplays_on"
+			// XDR displays (Ex. "spdisplays_built-in-liquid-retina-xdr", "spdisplays_pro-display-xdr") support HDR.
+			// system_profiler does not report ProMotion: it is a heuristic, the built-in XDR displays
+			// refreshing at 120 Hz being the ProMotion ones (the external displays are never flagged).
+			dInfo.HDR = strings.Contains(displayInfo.DisplayType, "xdr") || strings.Contains(displayInfo.DisplayType, "hdr")
+			dInfo.ProMotion = dInfo.BuiltIn && dInfo.HDR && dInfo.RefreshRateHz >= 120
+			displays = append(displays, dInfo)
+		}
+	}
+	return displays
+}
+
+func spFetchUptime(ctx context.Context, src *sources, spInfo *systemProfilerInfo, hostInfo *Info) error {
+	if len(spInfo.Software) == 0 {
+		return fmt.Errorf("system_profiler returned no software information")
+	}
+	uptimeInfoArr := strings.Split(strings.Split(spInfo.Software[0].Uptime, " ")[1], ":")
+	hostInfo.Uptime = fmt.Sprintf("%s days, %s hours", uptimeInfoArr[0], uptimeInfoArr[1])
+	return nil
+}
+
+// Fetch the model of the Mac. CALLED BY spFetchModel()
+// It comes in the form "MacBook Pro (16-inch, Nov 2024)".
+func fetchModelYear(ctx context.Context, runner CommandRunner, model *Model) {
+	model.Name = "Unknown"
+	output, err := runner.Run(ctx, "/usr/sbin/ioreg", "-arc", "IOPlatformDevice", "-k", "product-name")
+	if err != nil {
+		return
+	}
+	var r io.Reader = strings.NewReader(output)
+
+	var mapXml []map[string]interface{}
+	var input string
+	if err := plist.NewXMLDecoder(r).Decode(&mapXml); err != nil {
+		return
+	}
+	input = string(mapXml[0]["product-name"].([]byte))
+	// The last character of the "product-name" is unicode \u0000 (nul char), so let's remove it.
+	_, size := utf8.DecodeLastRuneInString(input)
+	input = input[:len(input)-size]
+
+	re := regexp.MustCompile(`^([\w\s]+)\s\(([^,]+),\s([^)]+)\)$`)
+	matches := re.FindStringSubmatch(input)
+
+	if len(matches) == 4 {
+		// "MacBook Pro" "16-inch" "Nov 2024"
+		model.Name = matches[1]
+		model.SubName = matches[2]
+		model.Date = matches[3]
+	}
+}
+
+/* ---------- Other macOS commands ---------- */
+
+// Memory pressure levels of kern.memorystatus_vm_pressure_level
+var darwinPressureLevels = map[string]string{"1": "normal", "2": "warning", "4": "critical"}
+
+func darwinFetchMemoryUsage(ctx context.Context, src *sources, hostInfo *Info) error {
+	output, err := src.opts.Runner.Run(ctx, "/usr/sbin/sysctl", "hw.memsize", "vm.swapusage", "kern.memorystatus_vm_pressure_level")
+	if err != nil {
+		return fmt.Errorf("sysctl: %w", err)
+	}
+	sysctl := parseSysctl(output)
+	usage := &MemoryUsage{Pressure: darwinPressureLevels[sysctl["kern.memorystatus_vm_pressure_level"]]}
+	if usage.TotalBytes, err = strconv.ParseUint(sysctl["hw.memsize"], 10, 64); err != nil {
+		return fmt.Errorf("invalid hw.memsize: %s", sysctl["hw.memsize"])
+	}
+	// Ex. "total = 2048.00M  used = 1024.50M  free = 1023.50M  (encrypted)"
+	if match := regexp.MustCompile(`total = ([\d.]+)M\s+used = ([\d.]+)M`).FindStringSubmatch(sysctl["vm.swapusage"]); match != nil {
+		total, _ := strconv.ParseFloat(match[1], 64)
+		used, _ := strconv.ParseFloat(match[2], 64)
+		usage.SwapTotalBytes = uint64(total * 1024 * 1024)
+		usage.SwapUsedBytes = uint64(used * 1024 * 1024)
+	}
+
+	output, err = src.opts.Runner.Run(ctx, "/usr/bin/vm_stat")
+	if err != nil {
+		return fmt.Errorf("vm_stat: %w", err)
+	}
+	pageSize, pages := parseVmStat(output)
+	// Like Activity Monitor: the used memory is the memory of the applications
+	// (anonymous pages which cannot be purged), the wired and the compressed memory.
+	app := max(pages["Anonymous pages"]-pages["Pages purgeable"], 0)
+	usage.WiredBytes = uint64(pages["Pages wired down"] * pageSize)
+	usage.CompressedBytes = uint64(pages["Pages occupied by compressor"] * pageSize)
+	usage.CachedBytes = uint64((pages["File-backed pages"] + pages["Pages purgeable"]) * pageSize)
+	usage.UsedBytes = min(uint64(app*pageSize)+usage.WiredBytes+usage.CompressedBytes, usage.TotalBytes)
+	usage.FreeBytes = usage.TotalBytes - usage.UsedBytes
+	usage.UsedPercent = math.Round(1000*float64(usage.UsedBytes)/float64(usage.TotalBytes)) / 10
+	hostInfo.MemoryUsage = usage
+	return nil
+}
+
+// parseSysctl parses the output of sysctl, Ex. "hw.memsize: 68719476736".
+func parseSysctl(output string) map[string]string {
+	values := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		if key, value, found := strings.Cut(line, ":"); found {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return values
+}
+
+// parseVmStat parses the output of vm_stat: the page size, and the numbers of pages by name.
+//
+//	Mach Virtual Memory Statistics: (page size of 16384 bytes)
+//	Pages free:                               12345.
+func parseVmStat(output string) (int, map[string]int) {
+	pageSize := 4096
+	if match := regexp.MustCompile(`page size of (\d+) bytes`).FindStringSubmatch(output); match != nil {
+		pageSize, _ = strconv.Atoi(match[1])
+	}
+	pages := map[string]int{}
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(value), ".")); err == nil {
+			pages[strings.TrimSpace(key)] = n
+		}
+	}
+	return pageSize, pages
+}
+
+func fetchDateTime(hostInfo *Info) {
+	hostInfo.Datetime = time.Now().Format(time.RFC1123)
+}
+
+// This functions fetches the number of installed applications:
+// - number of directories in /Applications.
+// - number of HomeBrew formulae.
+// - number of HomeBrew casks.
+func fetchSoftware(ctx context.Context, src *sources, hostInfo *Info) error {
+	hostInfo.Software = &SoftwareInfo{}
+	/* ---------- Number of directories in /Applications ---------- */
+	entries, err := os.ReadDir("/Applications")
+	if err != nil {
+		hostInfo.Software.NumApps = -1
+	} else {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				hostInfo.Software.NumApps++
+			}
+		}
+	}
+
+	/* ---------- Numner of HomeBrew Formulae/Casks ---------- */
+	// HomeBrew not being installed is not an error.
+	hostInfo.Software.NumBrewFormulae = -1
+	hostInfo.Software.NumBrewCasks = -1
+	filePath, err := which("brew")
+	if err != nil {
+		return nil
+	}
+
+	output, err := src.opts.Runner.Run(ctx, filePath, "list", "-1", "--formulae")
+	if err != nil {
+		return fmt.Errorf("brew list --formulae: %w", err)
+	}
+	hostInfo.Software.NumBrewFormulae = countNonEmptyLines(output)
+
+	output, err = src.opts.Runner.Run(ctx, filePath, "list", "-1", "--casks")
+	if err != nil {
+		return fmt.Errorf("brew list --casks: %w", err)
+	}
+	hostInfo.Software.NumBrewCasks = countNonEmptyLines(output)
+	return nil
+}
+
+// Fetch the terminal program using TERM_PROGRAM env. variable
+func fetchTermProgram(hostInfo *Info) {
+	termProgram := os.Getenv("TERM_PROGRAM")
+	if termProgram == "" {
+		hostInfo.Terminal = "Unknown"
+		return
+	}
+	hostInfo.Terminal = termProgram
+}
+
+// fetchWeather uses the weather cache file (see weatherCacheDuration)
+// if it is recent enough, otherwise it fetches the weather and writes the cache file.
+func fetchWeather(ctx context.Context, src *sources, hostInfo *Info) error {
+	cacheFile := src.opts.WeatherCacheFile
+	if cacheFile != "" && !src.opts.RefreshCache {
+		if isOlder, err := isFileOlderThan(cacheFile, weatherCacheDuration); err == nil && !isOlder {
+			tmpInfo := Info{}
+			if err := readCacheFile(cacheFile, &tmpInfo); err == nil && tmpInfo.Weather != nil {
+				hostInfo.Weather = tmpInfo.Weather
+				return nil
+			}
+		}
+	}
+	if err := fetchWeatherOpenMeteo(ctx, src, hostInfo); err != nil {
+		return err
+	}
+	if cacheFile == "" {
+		return nil
+	}
+	tmpInfo := Info{Weather: hostInfo.Weather}
+	if err := writeCacheFile(cacheFile, &tmpInfo); err != nil {
+		return fmt.Errorf("error writing weather cache: %w", err)
+	}
+	return nil
+}
+
+// getJSON makes an HTTP GET request and parses the JSON response into out.
+func getJSON(ctx context.Context, client *http.Client, url string, out any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	// Check for successful response
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", request.URL.Host, response.Status)
+	}
+
+	// Read the response body
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	// Parse the JSON response
+	if err = json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", request.URL.Host, err)
+	}
+	return nil
+}
+
+func fetchWeatherOpenMeteo(ctx context.Context, src *sources, hostInfo *Info) error {
+	weatherOpts := src.opts.Weather
+	var latitude, longitude *float64
+	var countryCode, locationName string
+	if weatherOpts.LocationName != "" {
+		var err error
+		latitude, longitude, countryCode, err = fetchCoordinatesFromName(
+			ctx,
+			weatherOpts.LocationName,
+			weatherOpts.LocationState,
+			weatherOpts.LocationCountry,
+		)
+		if err != nil {
+			return err
+		}
+		locationName = weatherOpts.LocationName
+	} else if weatherOpts.Latitude != nil && weatherOpts.Longitude != nil {
+		latitude = weatherOpts.Latitude
+		longitude = weatherOpts.Longitude
+	} else {
+		publicIp, err := src.publicIp()
+		if err != nil {
+			return fmt.Errorf("cannot locate public IP: %w", err)
+		}
+		latitude = &publicIp.Latitude
+		longitude = &publicIp.Longitude
+		countryCode = publicIp.CountryCode
+		locationName = publicIp.City
+	}
+
+	// Define the API URL
+	url := fmt.Sprintf(
+		"https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&current=temperature_2m,apparent_temperature,weather_code,wind_speed_10m,wind_direction_10m,wind_gusts_10m",
+		*latitude,
+		*longitude,
+	)
+	if weatherOpts.Units == "imperial" {
+		url += "&temperature_unit=fahrenheit&wind_speed_unit=mph&precipitation_unit=inch"
+	}
+
+	var openMeteo openMeteo
+	if err := getJSON(ctx, weatherHTTPClient, url, &openMeteo); err != nil {
+		return err
+	}
+
+	hostInfo.Weather = &Weather{}
+	hostInfo.Weather.TempUnit = openMeteo.CurrentUnits.Temperature2m
+	hostInfo.Weather.WindUnit = openMeteo.CurrentUnits.WindSpeed10m
+	hostInfo.Weather.Temperature = openMeteo.Current.Temperature2m
+	hostInfo.Weather.FeelsLike = openMeteo.Current.ApparentTemperature
+	hostInfo.Weather.WindSpeed = openMeteo.Current.WindSpeed10m
+	hostInfo.Weather.WindGusts = openMeteo.Current.WindGusts10m
+	hostInfo.Weather.WindDirection = openMeteo.Current.WindDirection10m
+	if descByLang, ok := wmoCodesDesc[openMeteo.Current.WeatherCode]; ok {
+		if desc, ok := descByLang[weatherOpts.Lang]; ok {
+			hostInfo.Weather.CurrentWeather = desc
+		} else if fallback, ok := descByLang["en"]; ok {
+			hostInfo.Weather.CurrentWeather = fallback
+		} else {
+			hostInfo.Weather.CurrentWeather = "Unknown"
+		}
+	} else {
+		hostInfo.Weather.CurrentWeather = "Unknown"
+	}
+	hostInfo.Weather.LocationCountryCode = countryCode
+	hostInfo.Weather.LocationName = locationName
+	hostInfo.Weather.Latitude = *latitude
+	hostInfo.Weather.Longitude = *longitude
+	return nil
+}
+
+func fetchCoordinatesFromName(ctx context.Context, locationName, locationState, locationCountry string) (latitude *float64, longitude *float64, countryCode string, err error) {
+	// Define the API URL
+	reqURL, err := url.Parse("https://geocoding-api.open-meteo.com/v1/search")
+	if err != nil {
+		return
+	}
+	query := reqURL.Query()
+	query.Set("name", locationName)
+	reqURL.RawQuery = query.Encode()
+
+	var geo openMeteoGeo
+	if err = getJSON(ctx, geoHTTPClient, reqURL.String(), &geo); err != nil {
+		return
+	}
+
+	upLocationName := strings.ToUpper(locationName)
+	upLocationState := ""
+	if locationState != "" {
+		upLocationState = strings.ToUpper(locationState)
+	}
+	upLocationCountry := strings.ToUpper(locationCountry)
+	for _, result := range geo.Results {
+		if strings.ToUpper(result.Name) == upLocationName &&
+			strings.ToUpper(result.Country) == upLocationCountry &&
+			((upLocationState != "" && strings.ToUpper(result.Admin1) == upLocationState) || upLocationState == "") {
+
+			latitude = new(float64)
+			longitude = new(float64)
+			*latitude = result.Latitude
+			*longitude = result.Longitude
+			countryCode = result.CountryCode
+			return
+		}
+	}
+	err = fmt.Errorf("location not found: %s (%s)", locationName, locationCountry)
+	return
+}
+
+type ipapiResponse struct {
+	IP          string  `json:"ip"`
+	City        string  `json:"city"`
+	Region      string  `json:"region"`
+	CountryName string  `json:"country_name"`
+	CountryCode string  `json:"country"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+}
+
+// Fetch the public IP address (and its country name)
+// The lookup is shared with the weather (see sources.publicIp).
+func fetchPublicIp(ctx context.Context, src *sources, hostInfo *Info) (err error) {
+	hostInfo.PublicIp, err = src.publicIp()
+	return
+}
+
+// lookupPublicIp returns the public IP address and its location.
+func lookupPublicIp(ctx context.Context) (*PublicIpInfo, error) {
+	// Unmarshal the JSON response into a tmp Struct,
+	// because in case of error we want the public IP to be nil.
+	tmpStruct := ipapiResponse{}
+	if err := getJSON(ctx, publicIPHTTPClient, "https://ipapi.co/json/", &tmpStruct); err != nil {
+		return nil, err
+	}
+	return &PublicIpInfo{
+		IP:          tmpStruct.IP,
+		Country:     tmpStruct.CountryName,
+		CountryCode: tmpStruct.CountryCode,
+		City:        tmpStruct.City,
+		State:       tmpStruct.Region,
+		Latitude:    tmpStruct.Latitude,
+		Longitude:   tmpStruct.Longitude,
+	}, nil
+}
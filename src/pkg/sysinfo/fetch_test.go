@@ -0,0 +1,135 @@
+package sysinfo
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Intel Macs with a discrete GPU: no cores, but the VRAM of each GPU
+func TestSpFetchGpuIntel(t *testing.T) {
+	output := `{"SPDisplaysDataType": [
+		{
+			"_name": "Intel UHD Graphics 630",
+			"spdisplays_metal": "spdisplays_supported",
+			"spdisplays_vendor": "Intel",
+			"spdisplays_vram_shared": "1536 MB",
+			"sppci_model": "Intel UHD Graphics 630"
+		},
+		{
+			"_name": "AMD Radeon Pro 5500M",
+			"spdisplays_mtlgpufamilysupport": "spdisplays_metal2",
+			"spdisplays_vendor": "sppci_vendor_amd",
+			"spdisplays_vram": "8 GB",
+			"sppci_model": "AMD Radeon Pro 5500M"
+		}
+	]}`
+	var spInfo systemProfilerInfo
+	if err := json.Unmarshal([]byte(output), &spInfo); err != nil {
+		t.Fatal(err)
+	}
+	var hostInfo Info
+	if err := spFetchGpu(context.Background(), nil, &spInfo, &hostInfo); err != nil {
+		t.Fatal(err)
+	}
+	expected := []Gpu{
+		{Vendor: "Intel", Model: "Intel UHD Graphics 630", VRAM: "1536 MB (shared)", Metal: "Metal"},
+		{Vendor: "AMD", Model: "AMD Radeon Pro 5500M", VRAM: "8 GB", Metal: "Metal 2"},
+	}
+	if !slices.Equal(hostInfo.Gpus, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, hostInfo.Gpus)
+	}
+}
+
+// A GPU without displays (Ex. headless Mac mini) is not an error.
+func TestSpFetchDisplays(t *testing.T) {
+	var spInfo systemProfilerInfo
+	if err := json.Unmarshal([]byte(`{"SPDisplaysDataType": [{"_name": "Apple M2", "spdisplays_ndrvs": [{
+		"_name": "LG HDR 4K",
+		"_spdisplays_pixels": "3840 x 2160",
+		"_spdisplays_resolution": "1920 x 1080 @ 60.00Hz",
+		"spdisplays_connection_type": "spdisplays_hdmi",
+		"spdisplays_main": "spdisplays_yes",
+		"spdisplays_mirror": "spdisplays_off",
+		"spdisplays_online": "spdisplays_yes"
+	}]}]}`), &spInfo); err != nil {
+		t.Fatal(err)
+	}
+	var hostInfo Info
+	if err := spFetchDisplays(context.Background(), nil, &spInfo, &hostInfo); err != nil {
+		t.Fatal(err)
+	}
+	expected := []Display{{
+		Name:             "LG HDR 4K",
+		PixelsWidth:      3840,
+		PixelsHeight:     2160,
+		ResolutionWidth:  1920,
+		ResolutionHeight: 1080,
+		RefreshRateHz:    60,
+		ScaleFactor:      2,
+		Connection:       "HDMI",
+		Main:             true,
+	}}
+	if !slices.Equal(hostInfo.Displays, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, hostInfo.Displays)
+	}
+
+	// No display (Ex. headless Mac mini) is not an error
+	spInfo = systemProfilerInfo{}
+	if err := json.Unmarshal([]byte(`{"SPDisplaysDataType": [{"_name": "Apple M2"}]}`), &spInfo); err != nil {
+		t.Fatal(err)
+	}
+	if err := spFetchDisplays(context.Background(), nil, &spInfo, &hostInfo); err != nil {
+		t.Fatal(err)
+	}
+	if len(hostInfo.Displays) != 0 {
+		t.Errorf("Expected no display, got %+v", hostInfo.Displays)
+	}
+}
+
+// system_profiler sometimes returns no SPDisplaysDataType information at all:
+// it is called again, once for both the gpu and the display items.
+func TestSpFetchDisplaysRetry(t *testing.T) {
+	dir := t.TempDir()
+	output := `{"SPDisplaysDataType": [{"_name": "Apple M2", "sppci_model": "Apple M2", "spdisplays_ndrvs": [{
+		"_name": "LG HDR 4K",
+		"_spdisplays_pixels": "3840 x 2160",
+		"spdisplays_connection_type": "spdisplays_hdmi",
+		"spdisplays_online": "spdisplays_yes"
+	}]}]}`
+	file := filepath.Join(dir, recordFileName("/usr/sbin/system_profiler", []string{"-json", "-detailLevel", "basic", SPDisplaysDataType}))
+	if err := os.WriteFile(file, []byte(output), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src := &sources{ctx: context.Background(), opts: &Options{Runner: ReplayRunner{Dir: dir}}}
+	empty := &systemProfilerInfo{}
+	var hostInfo Info
+	if err := spFetchDisplays(context.Background(), src, empty, &hostInfo); err != nil {
+		t.Fatal(err)
+	}
+	if len(hostInfo.Displays) != 1 || hostInfo.Displays[0].Name != "LG HDR 4K" {
+		t.Errorf("Expected the LG HDR 4K display, got %+v", hostInfo.Displays)
+	}
+	// The output of the retry is reused, system_profiler is not called again.
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := spFetchGpu(context.Background(), src, empty, &hostInfo); err != nil {
+		t.Fatal(err)
+	}
+	if len(hostInfo.Gpus) != 1 || hostInfo.Gpus[0].Model != "Apple M2" {
+		t.Errorf("Expected the Apple M2 GPU, got %+v", hostInfo.Gpus)
+	}
+
+	// Still no information after the retry
+	if err := os.WriteFile(file, []byte(`{"SPDisplaysDataType": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src = &sources{ctx: context.Background(), opts: &Options{Runner: ReplayRunner{Dir: dir}}}
+	if err := spFetchDisplays(context.Background(), src, empty, &hostInfo); err == nil {
+		t.Error("Expected an error without any display information")
+	}
+}
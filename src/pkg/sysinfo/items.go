@@ -0,0 +1,217 @@
+package sysinfo
+
+import (
+	"context"
+)
+
+/*
+This file contains the definition of all the items that can be fetched.
+*/
+
+// For things to be retrieved from system_profiler,
+// We need to know the SPDataType to fetch.
+const (
+	SPSoftwareDataType = "SPSoftwareDataType"
+	SPHardwareDataType = "SPHardwareDataType"
+	SPMemoryDataType   = "SPMemoryDataType"
+	SPDisplaysDataType = "SPDisplaysDataType"
+	SPPowerDataType    = "SPPowerDataType"
+	SPStorageDataType  = "SPStorageDataType"
+)
+
+func init() {
+	for _, c := range []*collector{
+		/* ---------- System Profiler Data (cached data) ---------- */
+		cpuCollector,
+		gpuCollector,
+		modelCollector,
+		memoryCollector,
+		serialNumberCollector,
+		/* ---------- System Profiler Data (non-cached data) ---------- */
+		batteryCollector,
+		diskCollector,
+		memoryUsageCollector,
+		volumesCollector,
+		displayCollector,
+		hostnameCollector,
+		osCollector,
+		systemIntegrityCollector,
+		uptimeCollector,
+		userCollector,
+		/* ---------- Other Data ---------- */
+		datetimeCollector,
+		publicIpCollector,
+		softwareCollector,
+		terminalCollector,
+		weatherCollector,
+	} {
+		registerCollector(c)
+	}
+}
+
+// simpleFetch adapts a function which cannot fail to the collector's fetch function.
+func simpleFetch(f func(*Info)) func(context.Context, *sources, *Info) error {
+	return func(_ context.Context, _ *sources, hostInfo *Info) error {
+		f(hostInfo)
+		return nil
+	}
+}
+
+/* ---------- System Profiler Data (cached data) ---------- */
+
+var cpuCollector = &collector{
+	name:       "cpu",
+	cached:     true,
+	spDataType: SPHardwareDataType,
+	fetchSP:    spFetchCpu,
+	fetchLinux: linuxFetchCpu,
+	field:      func(hostInfo *Info) any { return &hostInfo.Cpu },
+}
+
+var gpuCollector = &collector{
+	name:       "gpu",
+	cached:     true,
+	spDataType: SPDisplaysDataType,
+	fetchSP:    spFetchGpu,
+	fetchLinux: linuxFetchGpu,
+	field:      func(hostInfo *Info) any { return &hostInfo.Gpus },
+}
+
+var modelCollector = &collector{
+	name:       "model",
+	cached:     true,
+	spDataType: SPHardwareDataType,
+	fetchSP:    spFetchModel,
+	fetchLinux: linuxFetchModel,
+	field:      func(hostInfo *Info) any { return &hostInfo.Model },
+}
+
+var memoryCollector = &collector{
+	name:       "memory",
+	cached:     true,
+	spDataType: SPMemoryDataType,
+	fetchSP:    spFetchMemory,
+	fetchLinux: linuxFetchMemory,
+	field:      func(hostInfo *Info) any { return &hostInfo.Memory },
+}
+
+var serialNumberCollector = &collector{
+	name:       "serial_number",
+	cached:     true,
+	spDataType: SPHardwareDataType,
+	fetchSP:    spFetchSerialNumber,
+	fetchLinux: linuxFetchSerialNumber,
+	field:      func(hostInfo *Info) any { return &hostInfo.SerialNumber },
+}
+
+/* ---------- System Profiler Data (non-cached data) ---------- */
+
+var batteryCollector = &collector{
+	name:       "battery",
+	spDataType: SPPowerDataType,
+	fetchSP:    spFetchBattery,
+	fetchLinux: linuxFetchBattery,
+	field:      func(hostInfo *Info) any { return &hostInfo.Battery },
+}
+
+var diskCollector = &collector{
+	name:       "disk",
+	spDataType: SPStorageDataType,
+	fetchSP:    spFetchDisk,
+	fetchLinux: linuxFetchDisk,
+	field:      func(hostInfo *Info) any { return &hostInfo.Disk },
+}
+
+var memoryUsageCollector = &collector{
+	name:        "memory_usage",
+	fetchDarwin: darwinFetchMemoryUsage,
+	fetchLinux:  linuxFetchMemoryUsage,
+	field:       func(hostInfo *Info) any { return &hostInfo.MemoryUsage },
+}
+
+var volumesCollector = &collector{
+	name:       "volumes",
+	spDataType: SPStorageDataType,
+	fetchSP:    spFetchVolumes,
+	fetchLinux: linuxFetchVolumes,
+	field:      func(hostInfo *Info) any { return &hostInfo.Volumes },
+}
+
+var displayCollector = &collector{
+	name:       "display",
+	spDataType: SPDisplaysDataType,
+	fetchSP:    spFetchDisplays,
+	fetchLinux: linuxFetchDisplays,
+	field:      func(hostInfo *Info) any { return &hostInfo.Displays },
+}
+
+var hostnameCollector = &collector{
+	name:       "hostname",
+	spDataType: SPSoftwareDataType,
+	fetchSP:    spFetchHostname,
+	fetchLinux: linuxFetchHostname,
+	field:      func(hostInfo *Info) any { return &hostInfo.Hostname },
+}
+
+var osCollector = &collector{
+	name:       "os",
+	spDataType: SPSoftwareDataType,
+	fetchSP:    spFetchOs,
+	fetchLinux: linuxFetchOs,
+	field:      func(hostInfo *Info) any { return &hostInfo.Os },
+}
+
+var systemIntegrityCollector = &collector{
+	name:       "system_integrity",
+	spDataType: SPSoftwareDataType,
+	fetchSP:    spFetchSystemIntegrity,
+	field:      func(hostInfo *Info) any { return &hostInfo.SystemIntegrity },
+}
+
+var uptimeCollector = &collector{
+	name:       "uptime",
+	spDataType: SPSoftwareDataType,
+	fetchSP:    spFetchUptime,
+	fetchLinux: linuxFetchUptime,
+	field:      func(hostInfo *Info) any { return &hostInfo.Uptime },
+}
+
+var userCollector = &collector{
+	name:       "user",
+	spDataType: SPSoftwareDataType,
+	fetchSP:    spFetchUser,
+	fetchLinux: linuxFetchUser,
+	field:      func(hostInfo *Info) any { return &hostInfo.User },
+}
+
+/* ---------- Other Data ---------- */
+
+var datetimeCollector = &collector{
+	name:  "datetime",
+	fetch: simpleFetch(fetchDateTime),
+	field: func(hostInfo *Info) any { return &hostInfo.Datetime },
+}
+
+var publicIpCollector = &collector{
+	name:  "public_ip",
+	fetch: fetchPublicIp,
+	field: func(hostInfo *Info) any { return &hostInfo.PublicIp },
+}
+
+var softwareCollector = &collector{
+	name:  "software",
+	fetch: fetchSoftware,
+	field: func(hostInfo *Info) any { return &hostInfo.Software },
+}
+
+var terminalCollector = &collector{
+	name:  "terminal",
+	fetch: simpleFetch(fetchTermProgram),
+	field: func(hostInfo *Info) any { return &hostInfo.Terminal },
+}
+
+var weatherCollector = &collector{
+	name:  "weather",
+	fetch: fetchWeather,
+	field: func(hostInfo *Info) any { return &hostInfo.Weather },
+}
@@ -0,0 +1,634 @@
+package sysinfo
+
+/*
+This file contains the Linux counterpart of the system_profiler parsing.
+On Linux, there is no system_profiler, so the same "info" struct is filled
+from /proc, /sys, /etc/os-release and statfs.
+*/
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	linuxOsReleaseFile   = "/etc/os-release"
+	linuxCpuInfoFile     = "/proc/cpuinfo"
+	linuxMemInfoFile     = "/proc/meminfo"
+	linuxPressureFile    = "/proc/pressure/memory"
+	linuxUptimeFile      = "/proc/uptime"
+	linuxOsTypeFile      = "/proc/sys/kernel/ostype"
+	linuxOsReleaseKernel = "/proc/sys/kernel/osrelease"
+	linuxPowerSupplyDir  = "/sys/class/power_supply"
+	linuxDmiDir          = "/sys/class/dmi/id"
+	linuxDrmDir          = "/sys/class/drm"
+	linuxMountsFile      = "/proc/self/mounts"
+	linuxBlockDir        = "/sys/block"
+	// Database of the PCI vendors and devices names, depending on the distribution
+	linuxPciIdsFiles = []string{"/usr/share/hwdata/pci.ids", "/usr/share/misc/pci.ids", "/usr/share/pci.ids"}
+)
+
+// Names of the main GPU vendors, when pci.ids is not installed
+var linuxGpuVendors = map[string]string{
+	"0x1002": "AMD",
+	"0x10de": "NVIDIA",
+	"0x8086": "Intel",
+	"0x1af4": "Red Hat (virtio)",
+	"0x15ad": "VMware",
+}
+
+func linuxFetchModel(ctx context.Context, src *sources, hostInfo *Info) error {
+	hostInfo.Model = &Model{
+		Name:    readSysFile(filepath.Join(linuxDmiDir, "sys_vendor")),
+		SubName: readSysFile(filepath.Join(linuxDmiDir, "product_name")),
+		Date:    readSysFile(filepath.Join(linuxDmiDir, "bios_date")),
+		Number:  readSysFile(filepath.Join(linuxDmiDir, "product_version")),
+	}
+	if hostInfo.Model.Name == "" && hostInfo.Model.SubName == "" {
+		hostInfo.Model.Name = "Unknown"
+	}
+	return nil
+}
+
+func linuxFetchCpu(ctx context.Context, src *sources, hostInfo *Info) error {
+	data, err := os.ReadFile(linuxCpuInfoFile)
+	if err != nil {
+		return err
+	}
+	hostInfo.Cpu = parseCpuInfo(string(data))
+	return nil
+}
+
+func linuxFetchMemory(ctx context.Context, src *sources, hostInfo *Info) error {
+	data, err := os.ReadFile(linuxMemInfoFile)
+	if err != nil {
+		return err
+	}
+	memInfo := parseMemInfo(string(data))
+	// /proc/meminfo reports kB, we display GB like system_profiler does.
+	hostInfo.Memory = &Memory{
+		Amount: int(math.Round(float64(memInfo["MemTotal"]) / 1024 / 1024)),
+		Unit:   "GB",
+	}
+	return nil
+}
+
+func linuxFetchMemoryUsage(ctx context.Context, src *sources, hostInfo *Info) error {
+	data, err := os.ReadFile(linuxMemInfoFile)
+	if err != nil {
+		return err
+	}
+	memInfo := parseMemInfo(string(data))
+	// /proc/meminfo reports kB
+	kB := func(key string) uint64 { return uint64(memInfo[key]) * 1024 }
+	usage := &MemoryUsage{
+		TotalBytes:     kB("MemTotal"),
+		FreeBytes:      kB("MemAvailable"),
+		CachedBytes:    kB("Cached") + kB("Buffers") + kB("SReclaimable"),
+		SwapTotalBytes: kB("SwapTotal"),
+		SwapUsedBytes:  kB("SwapTotal") - kB("SwapFree"),
+	}
+	if usage.TotalBytes == 0 {
+		return fmt.Errorf("no MemTotal in %s", linuxMemInfoFile)
+	}
+	usage.UsedBytes = usage.TotalBytes - min(usage.FreeBytes, usage.TotalBytes)
+	usage.UsedPercent = math.Round(1000*float64(usage.UsedBytes)/float64(usage.TotalBytes)) / 10
+	// Pressure Stall Information (Linux 4.20+): share of the time the tasks waited for memory
+	if data, err := os.ReadFile(linuxPressureFile); err == nil {
+		usage.Pressure = parseMemoryPressure(string(data))
+	}
+	hostInfo.MemoryUsage = usage
+	return nil
+}
+
+// parseMemoryPressure returns the memory pressure level (normal, warning or critical)
+// from the content of /proc/pressure/memory, from the average of the last 10 seconds:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parseMemoryPressure(data string) string {
+	avg10 := map[string]float64{}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		if value, found := strings.CutPrefix(fields[1], "avg10="); found {
+			avg10[fields[0]], _ = strconv.ParseFloat(value, 64)
+		}
+	}
+	switch {
+	case avg10["full"] >= 10:
+		// All the tasks are stalled 10% of the time
+		return "critical"
+	case avg10["some"] >= 10:
+		return "warning"
+	}
+	return "normal"
+}
+
+func linuxFetchSerialNumber(ctx context.Context, src *sources, hostInfo *Info) error {
+	// product_serial is usually only readable by root.
+	serial := readSysFile(filepath.Join(linuxDmiDir, "product_serial"))
+	if serial == "" {
+		serial = "Unknown"
+	}
+	hostInfo.SerialNumber = &serial
+	return nil
+}
+
+func linuxFetchUser(ctx context.Context, src *sources, hostInfo *Info) error {
+	hostInfo.User = &UserInfo{}
+	u, err := user.Current()
+	if err != nil {
+		return err
+	}
+	// The GECOS field may contain more than the real name
+	// (e.g. "John Doe,,,"), so only keep the first part.
+	hostInfo.User.RealName = strings.Split(u.Name, ",")[0]
+	hostInfo.User.Login = u.Username
+	return nil
+}
+
+func linuxFetchHostname(ctx context.Context, src *sources, hostInfo *Info) (err error) {
+	hostInfo.Hostname, err = os.Hostname()
+	return
+}
+
+func linuxFetchOs(ctx context.Context, src *sources, hostInfo *Info) error {
+	data, err := os.ReadFile(linuxOsReleaseFile)
+	if err != nil {
+		return err
+	}
+	osRelease := parseOsRelease(string(data))
+	hostInfo.Os = &OsInfo{
+		System:                 osRelease["NAME"],
+		SystemVersion:          osRelease["VERSION_ID"],
+		SystemBuild:            osRelease["BUILD_ID"],
+		SystemVersionCodeNname: capitalizeFirstLetter(osRelease["VERSION_CODENAME"]),
+		KernelType:             readSysFile(linuxOsTypeFile),
+		KernelVersion:          readSysFile(linuxOsReleaseKernel),
+		Id:                     osRelease["ID"],
+		IdLike:                 strings.Fields(osRelease["ID_LIKE"]),
+	}
+	return nil
+}
+
+func linuxFetchDisk(ctx context.Context, src *sources, hostInfo *Info) error {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs("/", &stat); err != nil {
+		return err
+	}
+	totalBytes := uint64(stat.Blocks) * uint64(stat.Bsize)
+	freeBytes := uint64(stat.Bavail) * uint64(stat.Bsize)
+	hostInfo.Disk = &DiskInfo{
+		TotalTB:    float32(totalBytes) / 1000000000000,
+		FreeTB:     float32(freeBytes) / 1000000000000,
+		TotalBytes: totalBytes,
+		FreeBytes:  freeBytes,
+	}
+	return nil
+}
+
+// Virtual file systems, which are not listed by the volumes item
+var linuxPseudoFileSystems = []string{
+	"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2", "configfs", "debugfs", "devpts",
+	"devtmpfs", "efivarfs", "fusectl", "hugetlbfs", "mqueue", "nsfs", "proc", "pstore",
+	"ramfs", "rpc_pipefs", "securityfs", "squashfs", "sysfs", "tmpfs", "tracefs",
+}
+
+func linuxFetchVolumes(ctx context.Context, src *sources, hostInfo *Info) error {
+	data, err := os.ReadFile(linuxMountsFile)
+	if err != nil {
+		return err
+	}
+	hostInfo.Volumes = []Volume{}
+	devices := map[string]bool{}
+	for _, mount := range parseMounts(string(data)) {
+		// A device mounted several times (Ex. bind mounts) is only listed once.
+		if slices.Contains(linuxPseudoFileSystems, mount.fileSystem) || devices[mount.device] {
+			continue
+		}
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(mount.mountPoint, &stat); err != nil || stat.Blocks == 0 {
+			continue
+		}
+		volume := Volume{
+			Name:       filepath.Base(mount.device),
+			MountPoint: mount.mountPoint,
+			FileSystem: mount.fileSystem,
+			TotalBytes: uint64(stat.Blocks) * uint64(stat.Bsize),
+			FreeBytes:  uint64(stat.Bavail) * uint64(stat.Bsize),
+		}
+		if !src.opts.Volumes.matches(volume) {
+			continue
+		}
+		devices[mount.device] = true
+		// Only the block devices can be encrypted (Ex. not the network file systems).
+		if strings.HasPrefix(mount.device, "/dev/") {
+			encrypted := linuxIsEncrypted(mount.device)
+			volume.Encrypted = &encrypted
+		}
+		hostInfo.Volumes = append(hostInfo.Volumes, volume)
+	}
+	return nil
+}
+
+// A line of /proc/self/mounts
+type linuxMount struct {
+	device     string
+	mountPoint string
+	fileSystem string
+}
+
+// parseMounts parses the content of /proc/self/mounts.
+// The spaces (and tabs...) of the paths are escaped in octal, Ex. "\040" for a space.
+func parseMounts(data string) []linuxMount {
+	escaped := regexp.MustCompile(`\\[0-7]{3}`)
+	unescape := func(s string) string {
+		return escaped.ReplaceAllStringFunc(s, func(octal string) string {
+			code, _ := strconv.ParseUint(octal[1:], 8, 8)
+			return string(rune(code))
+		})
+	}
+	var mounts []linuxMount
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		mounts = append(mounts, linuxMount{
+			device:     unescape(fields[0]),
+			mountPoint: unescape(fields[1]),
+			fileSystem: fields[2],
+		})
+	}
+	return mounts
+}
+
+// linuxIsEncrypted returns whether a block device is encrypted with dm-crypt (LUKS),
+// directly or through the devices it is built on (Ex. LVM on LUKS).
+func linuxIsEncrypted(device string) bool {
+	if resolved, err := filepath.EvalSymlinks(device); err == nil {
+		device = resolved // Ex. /dev/mapper/root -> /dev/dm-0
+	}
+	name := filepath.Base(device)
+	if strings.HasPrefix(readSysFile(filepath.Join(linuxBlockDir, name, "dm", "uuid")), "CRYPT-") {
+		return true
+	}
+	slaves, _ := os.ReadDir(filepath.Join(linuxBlockDir, name, "slaves"))
+	for _, slave := range slaves {
+		if linuxIsEncrypted(filepath.Join("/dev", slave.Name())) {
+			return true
+		}
+	}
+	return false
+}
+
+func linuxFetchUptime(ctx context.Context, src *sources, hostInfo *Info) error {
+	data, err := os.ReadFile(linuxUptimeFile)
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return fmt.Errorf("no uptime in %s", linuxUptimeFile)
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return err
+	}
+	uptime := time.Duration(seconds) * time.Second
+	hostInfo.Uptime = fmt.Sprintf("%d days, %d hours", int(uptime.Hours())/24, int(uptime.Hours())%24)
+	return nil
+}
+
+// readSysFile returns the trimmed content of a (small) file,
+// or an empty string if it cannot be read.
+func readSysFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// parseOsRelease parses the content of /etc/os-release (KEY="value" lines).
+func parseOsRelease(data string) map[string]string {
+	osRelease := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `"'`)
+		}
+		osRelease[key] = value
+	}
+	return osRelease
+}
+
+// parseCpuInfo parses the content of /proc/cpuinfo.
+// Cores is the number of logical processors.
+func parseCpuInfo(data string) *Cpu {
+	cpu := &Cpu{}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		switch key {
+		case "processor":
+			cpu.Cores++
+		case "model name", "Model":
+			// "Model" is used on some ARM boards (e.g. Raspberry Pi)
+			if cpu.Model == "" {
+				cpu.Model = value
+			}
+		}
+	}
+	if cpu.Model == "" {
+		cpu.Model = arch
+	}
+	return cpu
+}
+
+// parseMemInfo parses the content of /proc/meminfo.
+// The values are returned in kB (as found in the file).
+func parseMemInfo(data string) map[string]int {
+	memInfo := map[string]int{}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		if v, err := strconv.Atoi(fields[0]); err == nil {
+			memInfo[key] = v
+		}
+	}
+	return memInfo
+}
+
+// linuxFetchBattery fetches the information of the first battery, and of the AC adapter,
+// found in /sys/class/power_supply. hostInfo.Battery is left nil if there is no battery.
+func linuxFetchBattery(ctx context.Context, src *sources, hostInfo *Info) error {
+	entries, err := os.ReadDir(linuxPowerSupplyDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var battery *BatteryInfo
+	var adapter *ACAdapterInfo
+	for _, entry := range entries {
+		dir := filepath.Join(linuxPowerSupplyDir, entry.Name())
+		switch readSysFile(filepath.Join(dir, "type")) {
+		case "Battery":
+			if battery == nil {
+				battery = linuxReadBattery(dir)
+			}
+		case "Mains", "USB":
+			// Several adapters (Ex. USB-C ports): the connected one is kept.
+			if adapter == nil || !adapter.Connected {
+				adapter = linuxReadACAdapter(dir)
+			}
+		}
+	}
+	if battery != nil {
+		battery.ACAdapter = adapter
+		hostInfo.Battery = battery
+	}
+	return nil
+}
+
+// linuxReadBattery reads the information of the battery of a power_supply directory.
+func linuxReadBattery(dir string) *BatteryInfo {
+	battery := &BatteryInfo{}
+	battery.StatusPercent, _ = strconv.Atoi(readSysFile(filepath.Join(dir, "capacity")))
+	status := readSysFile(filepath.Join(dir, "status"))
+	battery.Charging = status == "Charging" || status == "Full"
+	battery.FullyCharged = status == "Full"
+	level := readSysFile(filepath.Join(dir, "capacity_level"))
+	battery.AtWarnLevel = level == "Low" || level == "Critical"
+	battery.CycleCount, _ = strconv.Atoi(readSysFile(filepath.Join(dir, "cycle_count")))
+
+	// Depending on the driver, the battery reports energy (µWh, with power in µW)
+	// or charge (µAh, with current in µA).
+	for _, unit := range []struct{ amount, rate string }{{"energy", "power"}, {"charge", "current"}} {
+		full, errFull := strconv.Atoi(readSysFile(filepath.Join(dir, unit.amount+"_full")))
+		design, errDesign := strconv.Atoi(readSysFile(filepath.Join(dir, unit.amount+"_full_design")))
+		if errFull != nil || errDesign != nil || design <= 0 {
+			continue
+		}
+		battery.CapacityPercent = int(math.Round(float64(full) * 100 / float64(design)))
+
+		now, errNow := strconv.Atoi(readSysFile(filepath.Join(dir, unit.amount+"_now")))
+		rate, errRate := strconv.Atoi(readSysFile(filepath.Join(dir, unit.rate+"_now")))
+		if errNow == nil && errRate == nil && rate > 0 {
+			switch status {
+			case "Discharging":
+				battery.TimeToEmptyMinutes = int(math.Round(float64(now) * 60 / float64(rate)))
+			case "Charging":
+				battery.TimeToFullMinutes = int(math.Round(float64(full-now) * 60 / float64(rate)))
+			}
+		}
+		break
+	}
+	if battery.Health = readSysFile(filepath.Join(dir, "health")); battery.Health == "" {
+		battery.Health = "Unknown"
+	}
+	return battery
+}
+
+// linuxReadACAdapter reads the information of the AC adapter of a power_supply directory.
+// The wattage is only known if the adapter reports its voltage (µV) and maximum current (µA).
+func linuxReadACAdapter(dir string) *ACAdapterInfo {
+	adapter := &ACAdapterInfo{Connected: readSysFile(filepath.Join(dir, "online")) == "1"}
+	voltage, errVoltage := strconv.Atoi(readSysFile(filepath.Join(dir, "voltage_now")))
+	current, errCurrent := strconv.Atoi(readSysFile(filepath.Join(dir, "current_max")))
+	if adapter.Connected && errVoltage == nil && errCurrent == nil {
+		adapter.Watts = int(math.Round(float64(voltage) * float64(current) / 1e12))
+	}
+	return adapter
+}
+
+// linuxFetchGpu fetches the GPUs (DRM cards) found in /sys/class/drm, named from pci.ids.
+// hostInfo.Gpus is left empty if there is no GPU (Ex. headless server).
+func linuxFetchGpu(ctx context.Context, src *sources, hostInfo *Info) error {
+	// The connectors (Ex. card0-HDMI-A-1) are not GPUs.
+	cards, err := filepath.Glob(filepath.Join(linuxDrmDir, "card[0-9]*"))
+	if err != nil {
+		return err
+	}
+	var pciIds string
+	for _, file := range linuxPciIdsFiles {
+		if data, err := os.ReadFile(file); err == nil {
+			pciIds = string(data)
+			break
+		}
+	}
+	hostInfo.Gpus = []Gpu{}
+	for _, card := range cards {
+		if strings.Contains(filepath.Base(card), "-") {
+			continue
+		}
+		device := filepath.Join(card, "device")
+		vendorId := readSysFile(filepath.Join(device, "vendor"))
+		deviceId := readSysFile(filepath.Join(device, "device"))
+		if vendorId == "" {
+			continue
+		}
+		gpu := Gpu{}
+		gpu.Vendor, gpu.Model = lookupPciIds(pciIds, vendorId, deviceId)
+		// The names of pci.ids are long, Ex. "Advanced Micro Devices, Inc. [AMD/ATI]"
+		if name, ok := linuxGpuVendors[vendorId]; ok {
+			gpu.Vendor = name
+		}
+		if gpu.Model == "" {
+			gpu.Model = "device " + deviceId
+		}
+		// Only reported by the amdgpu driver
+		if vram, err := strconv.ParseUint(readSysFile(filepath.Join(device, "mem_info_vram_total")), 10, 64); err == nil && vram > 0 {
+			// Like system_profiler, Ex. "8 GB" or "512 MB"
+			if vram >= 1024*1024*1024 {
+				gpu.VRAM = fmt.Sprintf("%.0f GB", float64(vram)/1024/1024/1024)
+			} else {
+				gpu.VRAM = fmt.Sprintf("%d MB", vram/1024/1024)
+			}
+		}
+		hostInfo.Gpus = append(hostInfo.Gpus, gpu)
+	}
+	return nil
+}
+
+// lookupPciIds returns the names of a PCI vendor and device (Ex. "0x10de" and "0x2684"),
+// from the content of pci.ids:
+//
+//	10de  NVIDIA Corporation
+//		2684  AD102 [GeForce RTX 4090]
+//
+// The model is the marketing name between brackets, if any.
+func lookupPciIds(data, vendorId, deviceId string) (vendor, model string) {
+	vendorId = strings.TrimPrefix(vendorId, "0x")
+	deviceId = strings.TrimPrefix(deviceId, "0x")
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "#") || line == "":
+			continue
+		case !strings.HasPrefix(line, "\t"):
+			// A vendor line: the devices of our vendor are over.
+			if vendor != "" {
+				return
+			}
+			if id, name, found := strings.Cut(line, "  "); found && id == vendorId {
+				vendor = name
+			}
+		case vendor != "" && !strings.HasPrefix(line, "\t\t"): // not a subsystem
+			if id, name, found := strings.Cut(line[1:], "  "); found && id == deviceId {
+				model = name
+				if start, end := strings.Index(name, "["), strings.LastIndex(name, "]"); start >= 0 && end > start {
+					model = name[start+1 : end]
+				}
+				return
+			}
+		}
+	}
+	return
+}
+
+// Connection types of the DRM connectors, Ex. card1-HDMI-A-1
+var linuxConnectionTypes = map[string]string{
+	"DP":      "DisplayPort",
+	"HDMI-A":  "HDMI",
+	"HDMI-B":  "HDMI",
+	"DVI-D":   "DVI",
+	"DVI-I":   "DVI",
+	"Virtual": "Virtual",
+}
+
+// linuxFetchDisplays fetches the connected displays found in /sys/class/drm.
+// Only the preferred mode (first line of "modes") is known,
+// so pixels and resolution are the same.
+func linuxFetchDisplays(ctx context.Context, src *sources, hostInfo *Info) error {
+	connectors, err := filepath.Glob(filepath.Join(linuxDrmDir, "card*-*"))
+	if err != nil {
+		return err
+	}
+	for _, connector := range connectors {
+		if readSysFile(filepath.Join(connector, "status")) != "connected" {
+			continue
+		}
+		modes := strings.Fields(readSysFile(filepath.Join(connector, "modes")))
+		if len(modes) == 0 {
+			continue
+		}
+		width, height, found := strings.Cut(modes[0], "x")
+		if !found {
+			continue
+		}
+		d := Display{}
+		d.PixelsWidth, _ = strconv.Atoi(width)
+		d.PixelsHeight, _ = strconv.Atoi(strings.TrimRightFunc(height, func(r rune) bool { return r < '0' || r > '9' }))
+		d.ResolutionWidth = d.PixelsWidth
+		d.ResolutionHeight = d.PixelsHeight
+
+		// "card0-eDP-1" -> "eDP", "card1-HDMI-A-1" -> "HDMI-A"
+		_, connection, _ := strings.Cut(filepath.Base(connector), "-")
+		connection = connection[:max(strings.LastIndex(connection, "-"), 0)]
+		d.Connection = connection
+		if name, ok := linuxConnectionTypes[connection]; ok {
+			d.Connection = name
+		}
+		d.BuiltIn = slices.Contains([]string{"eDP", "LVDS", "DSI"}, connection)
+		if edid, err := os.ReadFile(filepath.Join(connector, "edid")); err == nil {
+			d.Name = parseEdidName(edid)
+		}
+		hostInfo.Displays = append(hostInfo.Displays, d)
+	}
+	return nil
+}
+
+// parseEdidName returns the name of the monitor from its EDID, Ex. "DELL U2723QE".
+// It is in one of the 4 display descriptors (18 bytes each, from byte 54) of type 0xFC.
+func parseEdidName(edid []byte) string {
+	for offset := 54; offset+18 <= min(len(edid), 126); offset += 18 {
+		descriptor := edid[offset : offset+18]
+		if descriptor[0] == 0 && descriptor[1] == 0 && descriptor[3] == 0xFC {
+			name, _, _ := strings.Cut(string(descriptor[5:]), "\n")
+			return strings.TrimSpace(name)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,255 @@
+package sysinfo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseOsRelease(t *testing.T) {
+	content := `PRETTY_NAME="Ubuntu 24.04.1 LTS"
+NAME="Ubuntu"
+VERSION_ID="24.04"
+# a comment
+VERSION_CODENAME=noble
+ID=ubuntu
+ID_LIKE=debian
+`
+	osRelease := parseOsRelease(content)
+	expected := map[string]string{
+		"NAME":             "Ubuntu",
+		"VERSION_ID":       "24.04",
+		"VERSION_CODENAME": "noble",
+		"ID":               "ubuntu",
+		"ID_LIKE":          "debian",
+	}
+	for k, v := range expected {
+		if osRelease[k] != v {
+			t.Errorf("Expected %s to be '%s', got '%s'", k, v, osRelease[k])
+		}
+	}
+}
+
+func TestParseCpuInfo(t *testing.T) {
+	content := `processor	: 0
+vendor_id	: GenuineIntel
+model name	: 11th Gen Intel(R) Core(TM) i7-1165G7 @ 2.80GHz
+
+processor	: 1
+vendor_id	: GenuineIntel
+model name	: 11th Gen Intel(R) Core(TM) i7-1165G7 @ 2.80GHz
+`
+	cpu := parseCpuInfo(content)
+	if cpu.Model != "11th Gen Intel(R) Core(TM) i7-1165G7 @ 2.80GHz" {
+		t.Errorf("Unexpected CPU model: '%s'", cpu.Model)
+	}
+	if cpu.Cores != 2 {
+		t.Errorf("Expected 2 cores, got %d", cpu.Cores)
+	}
+}
+
+func TestParseMemInfo(t *testing.T) {
+	content := `MemTotal:       16283468 kB
+MemFree:         1153000 kB
+HugePages_Total:       0
+`
+	memInfo := parseMemInfo(content)
+	if memInfo["MemTotal"] != 16283468 {
+		t.Errorf("Expected MemTotal to be 16283468, got %d", memInfo["MemTotal"])
+	}
+	if memInfo["HugePages_Total"] != 0 {
+		t.Errorf("Expected HugePages_Total to be 0, got %d", memInfo["HugePages_Total"])
+	}
+}
+
+func TestLinuxReadBattery(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"capacity":           "47",
+		"capacity_level":     "Normal",
+		"status":             "Discharging",
+		"cycle_count":        "312",
+		"energy_full":        "45000000",
+		"energy_full_design": "50000000",
+		"energy_now":         "21000000",
+		"power_now":          "7000000",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	battery := linuxReadBattery(dir)
+	if battery.StatusPercent != 47 || battery.CapacityPercent != 90 || battery.CycleCount != 312 {
+		t.Errorf("Unexpected battery: %+v", battery)
+	}
+	if battery.TimeToEmptyMinutes != 180 || battery.TimeToFullMinutes != 0 {
+		t.Errorf("Expected 180 minutes to empty, got %+v", battery)
+	}
+	if battery.Charging || battery.FullyCharged || battery.AtWarnLevel {
+		t.Errorf("Unexpected flags: %+v", battery)
+	}
+}
+
+func TestParseMounts(t *testing.T) {
+	content := `/dev/mapper/vg-root / ext4 rw,relatime 0 0
+proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sdb1 /media/jdoe/My\040Disk exfat rw,nosuid,nodev 0 0
+`
+	mounts := parseMounts(content)
+	if len(mounts) != 3 {
+		t.Fatalf("Expected 3 mounts, got %d", len(mounts))
+	}
+	expected := linuxMount{device: "/dev/sdb1", mountPoint: "/media/jdoe/My Disk", fileSystem: "exfat"}
+	if mounts[2] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, mounts[2])
+	}
+}
+
+func TestLinuxFetchVolumes(t *testing.T) {
+	mountsFile := linuxMountsFile
+	t.Cleanup(func() { linuxMountsFile = mountsFile })
+	dir := t.TempDir()
+	linuxMountsFile = filepath.Join(dir, "mounts")
+
+	// The same device mounted twice, the first mount point being excluded
+	excluded, listed := filepath.Join(dir, "excluded"), filepath.Join(dir, "listed")
+	for _, mountPoint := range []string{excluded, listed} {
+		if err := os.Mkdir(mountPoint, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	content := "/dev/sdz1 " + excluded + " ext4 rw 0 0\n/dev/sdz1 " + listed + " ext4 rw 0 0\n"
+	if err := os.WriteFile(linuxMountsFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src := &sources{opts: &Options{Volumes: VolumeFilter{ExcludeMountPoints: []string{excluded}}}}
+	var hostInfo Info
+	if err := linuxFetchVolumes(context.Background(), src, &hostInfo); err != nil {
+		t.Fatal(err)
+	}
+	if len(hostInfo.Volumes) != 1 || hostInfo.Volumes[0].MountPoint != listed {
+		t.Errorf("Expected the volume mounted at %s, got %+v", listed, hostInfo.Volumes)
+	}
+}
+
+func TestLookupPciIds(t *testing.T) {
+	content := `# List of PCI ID's
+10de  NVIDIA Corporation
+	2684  AD102 [GeForce RTX 4090]
+		10de 165b  GeForce RTX 4090
+	2704  AD103
+8086  Intel Corporation
+	a7a0  Raptor Lake-P [Iris Xe Graphics]
+`
+	tests := []struct {
+		vendorId, deviceId string
+		vendor, model      string
+	}{
+		{"0x10de", "0x2684", "NVIDIA Corporation", "GeForce RTX 4090"},
+		{"0x10de", "0x2704", "NVIDIA Corporation", "AD103"},
+		{"0x8086", "0xa7a0", "Intel Corporation", "Iris Xe Graphics"},
+		{"0x10de", "0x9999", "NVIDIA Corporation", ""},
+		{"0x1002", "0x744c", "", ""},
+	}
+	for _, test := range tests {
+		vendor, model := lookupPciIds(content, test.vendorId, test.deviceId)
+		if vendor != test.vendor || model != test.model {
+			t.Errorf("lookupPciIds(%s, %s) = %q, %q, expected %q, %q", test.vendorId, test.deviceId, vendor, model, test.vendor, test.model)
+		}
+	}
+}
+
+func TestLinuxFetchGpu(t *testing.T) {
+	drmDir, pciIdsFiles := linuxDrmDir, linuxPciIdsFiles
+	t.Cleanup(func() { linuxDrmDir, linuxPciIdsFiles = drmDir, pciIdsFiles })
+	linuxDrmDir, linuxPciIdsFiles = t.TempDir(), nil
+
+	for path, content := range map[string]string{
+		"card1/device/vendor":              "0x1002",
+		"card1/device/device":              "0x744c",
+		"card1/device/mem_info_vram_total": "25753026560",
+		"card1-DP-1/status":                "connected",
+	} {
+		path = filepath.Join(linuxDrmDir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var hostInfo Info
+	if err := linuxFetchGpu(context.Background(), nil, &hostInfo); err != nil {
+		t.Fatal(err)
+	}
+	expected := []Gpu{{Vendor: "AMD", Model: "device 0x744c", VRAM: "24 GB"}}
+	if !slices.Equal(hostInfo.Gpus, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, hostInfo.Gpus)
+	}
+
+	// No GPU (Ex. headless server) is not an error
+	linuxDrmDir = t.TempDir()
+	if err := linuxFetchGpu(context.Background(), nil, &hostInfo); err != nil {
+		t.Fatal(err)
+	}
+	if hostInfo.Gpus == nil || len(hostInfo.Gpus) != 0 {
+		t.Errorf("Expected no GPU, got %+v", hostInfo.Gpus)
+	}
+}
+
+func TestParseEdidName(t *testing.T) {
+	edid := make([]byte, 128)
+	// Second descriptor: serial number, third one: monitor name
+	copy(edid[72:], []byte{0, 0, 0, 0xFF, 0})
+	copy(edid[77:], "ABC123\n")
+	copy(edid[90:], []byte{0, 0, 0, 0xFC, 0})
+	copy(edid[95:], "DELL U2723QE\n")
+	if name := parseEdidName(edid); name != "DELL U2723QE" {
+		t.Errorf("Expected DELL U2723QE, got %q", name)
+	}
+	if name := parseEdidName(edid[:60]); name != "" {
+		t.Errorf("Expected no name for a truncated EDID, got %q", name)
+	}
+}
+
+func TestParseMemoryPressure(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n", "normal"},
+		{"some avg10=24.51 avg60=8.02 avg300=1.90 total=9123\nfull avg10=3.12 avg60=1.00 avg300=0.20 total=1234\n", "warning"},
+		{"some avg10=80.00 avg60=40.00 avg300=10.00 total=9123\nfull avg10=35.00 avg60=12.00 avg300=3.00 total=1234\n", "critical"},
+	}
+	for _, test := range tests {
+		if actual := parseMemoryPressure(test.content); actual != test.expected {
+			t.Errorf("Expected %s, got %s for %q", test.expected, actual, test.content)
+		}
+	}
+}
+
+func TestLinuxFetchUptime(t *testing.T) {
+	uptimeFile := linuxUptimeFile
+	t.Cleanup(func() { linuxUptimeFile = uptimeFile })
+	linuxUptimeFile = filepath.Join(t.TempDir(), "uptime")
+
+	if err := os.WriteFile(linuxUptimeFile, []byte("154800.42 1210000.17\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var hostInfo Info
+	if err := linuxFetchUptime(context.Background(), nil, &hostInfo); err != nil {
+		t.Fatal(err)
+	}
+	if hostInfo.Uptime != "1 days, 19 hours" {
+		t.Errorf("Expected 1 days, 19 hours, got %q", hostInfo.Uptime)
+	}
+
+	if err := os.WriteFile(linuxUptimeFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := linuxFetchUptime(context.Background(), nil, &hostInfo); err == nil {
+		t.Error("Expected an error for an empty uptime file")
+	}
+}
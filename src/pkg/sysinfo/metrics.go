@@ -0,0 +1,220 @@
+package sysinfo
+
+/*
+This file renders the collected information as OpenMetrics text
+(https://openmetrics.io), Ex. for the node_exporter textfile collector.
+All the metrics are gauges, labeled with the hostname, model and serial number.
+*/
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const metricsPrefix = "minfo_"
+
+// metricFamily is a metric with its samples (one per set of extra labels).
+type metricFamily struct {
+	name    string
+	help    string
+	samples []metricSample
+}
+
+type metricSample struct {
+	labels [][2]string // extra labels (besides hostname, model and serial)
+	value  float64
+}
+
+// metrics builds the metric families, in order.
+type metrics struct {
+	families []*metricFamily
+}
+
+// add adds a sample to the metric family name (created if needed).
+// labels are pairs of label name and value.
+func (m *metrics) add(name, help string, value float64, labels ...string) {
+	idx := slices.IndexFunc(m.families, func(f *metricFamily) bool { return f.name == name })
+	if idx < 0 {
+		m.families = append(m.families, &metricFamily{name: name, help: help})
+		idx = len(m.families) - 1
+	}
+	sample := metricSample{value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		sample.labels = append(sample.labels, [2]string{labels[i], labels[i+1]})
+	}
+	m.families[idx].samples = append(m.families[idx].samples, sample)
+}
+
+// WriteMetrics writes the information (and the items which could not be
+// fetched, see Errors) as OpenMetrics text.
+// Only the information which is set in hostInfo is written.
+func WriteMetrics(w io.Writer, hostInfo *Info, errs Errors) error {
+	var m metrics
+
+	/* ---------- Information ---------- */
+	infoLabels := []string{}
+	if hostInfo.Os != nil {
+		infoLabels = append(infoLabels, "os", hostInfo.Os.System, "os_version", hostInfo.Os.SystemVersion)
+	}
+	if hostInfo.Cpu != nil {
+		infoLabels = append(infoLabels, "cpu", hostInfo.Cpu.Model)
+	}
+	m.add("info", "Information about the host.", 1, infoLabels...)
+
+	/* ---------- Hardware ---------- */
+	if hostInfo.Cpu != nil {
+		m.add("cpu_cores", "Number of CPU cores.", float64(hostInfo.Cpu.Cores))
+	}
+	for i, gpu := range hostInfo.Gpus {
+		// The number of cores is only known on Apple Silicon
+		if gpu.Cores == 0 {
+			continue
+		}
+		// The index tells apart identical GPUs
+		m.add("gpu_cores", "Number of GPU cores.", float64(gpu.Cores), "index", strconv.Itoa(i), "gpu", gpu.Model)
+	}
+	if hostInfo.Memory != nil {
+		if bytes, ok := memoryBytes(hostInfo.Memory); ok {
+			m.add("memory_bytes", "Amount of memory installed.", bytes)
+		}
+	}
+	if hostInfo.Disk != nil {
+		m.add("disk_size_bytes", "Size of the startup disk.", float64(hostInfo.Disk.TotalBytes))
+		m.add("disk_free_bytes", "Available space on the startup disk.", float64(hostInfo.Disk.FreeBytes))
+		if hostInfo.Disk.SmartStatus != "" {
+			m.add("disk_smart_verified", "Whether the SMART status of the startup disk is verified.",
+				boolToFloat(hostInfo.Disk.SmartStatus == "Verified"), "status", hostInfo.Disk.SmartStatus)
+		}
+	}
+	if usage := hostInfo.MemoryUsage; usage != nil {
+		m.add("memory_used_bytes", "Memory used by the applications and the system (not the files cache).", float64(usage.UsedBytes))
+		m.add("memory_swap_used_bytes", "Swap space used.", float64(usage.SwapUsedBytes))
+		if usage.Pressure != "" {
+			m.add("memory_pressure", "Memory pressure (as a label).", 1, "pressure", usage.Pressure)
+		}
+	}
+	for _, volume := range hostInfo.Volumes {
+		m.add("volume_size_bytes", "Size of the mounted volume.", float64(volume.TotalBytes), "mount_point", volume.MountPoint)
+		m.add("volume_free_bytes", "Available space on the mounted volume.", float64(volume.FreeBytes), "mount_point", volume.MountPoint)
+	}
+	if hostInfo.Battery != nil {
+		m.add("battery_charge_ratio", "State of charge of the battery.", float64(hostInfo.Battery.StatusPercent)/100)
+		m.add("battery_capacity_ratio", "Maximum capacity of the battery, compared to its design capacity.", float64(hostInfo.Battery.CapacityPercent)/100)
+		m.add("battery_charging", "Whether the battery is charging.", boolToFloat(hostInfo.Battery.Charging))
+		m.add("battery_health", "Health of the battery (as a label).", 1, "health", hostInfo.Battery.Health)
+		m.add("battery_cycle_count", "Number of charge cycles of the battery.", float64(hostInfo.Battery.CycleCount))
+		if hostInfo.Battery.ACAdapter != nil {
+			m.add("ac_adapter_connected", "Whether the AC adapter is connected.", boolToFloat(hostInfo.Battery.ACAdapter.Connected))
+		}
+	}
+	if hostInfo.Displays != nil {
+		m.add("displays", "Number of displays.", float64(len(hostInfo.Displays)))
+	}
+
+	/* ---------- Software ---------- */
+	if uptime, ok := uptimeSeconds(hostInfo.Uptime); ok {
+		m.add("uptime_seconds", "Time since the host was booted (hour precision).", uptime)
+	}
+	if hostInfo.Software != nil {
+		// -1 means unknown (Ex. HomeBrew not installed)
+		if hostInfo.Software.NumApps >= 0 {
+			m.add("software_apps", "Number of applications in /Applications.", float64(hostInfo.Software.NumApps))
+		}
+		if hostInfo.Software.NumBrewFormulae >= 0 {
+			m.add("software_homebrew_formulae", "Number of HomeBrew formulae installed.", float64(hostInfo.Software.NumBrewFormulae))
+		}
+		if hostInfo.Software.NumBrewCasks >= 0 {
+			m.add("software_homebrew_casks", "Number of HomeBrew casks installed.", float64(hostInfo.Software.NumBrewCasks))
+		}
+	}
+
+	/* ---------- Weather ---------- */
+	if hostInfo.Weather != nil {
+		unit := "celsius"
+		if strings.Contains(hostInfo.Weather.TempUnit, "F") {
+			unit = "fahrenheit"
+		}
+		m.add("weather_temperature_"+unit, "Current temperature at the location of the host.", hostInfo.Weather.Temperature)
+		m.add("weather_apparent_temperature_"+unit, "Current apparent (feels like) temperature at the location of the host.", hostInfo.Weather.FeelsLike)
+	}
+
+	/* ---------- Errors ---------- */
+	for _, name := range slices.Sorted(maps.Keys(errs)) {
+		m.add("item_error", "Whether the item could not be fetched.", 1, "item", name)
+	}
+
+	return m.write(w, hostLabels(hostInfo))
+}
+
+// write writes all the metric families, with the host labels on every sample.
+func (m *metrics) write(w io.Writer, hostLabels [][2]string) error {
+	var b strings.Builder
+	for _, f := range m.families {
+		name := metricsPrefix + f.name
+		fmt.Fprintf(&b, "# HELP %s %s\n", name, f.help)
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", name)
+		for _, s := range f.samples {
+			var labels []string
+			for _, l := range append(slices.Clone(hostLabels), s.labels...) {
+				labels = append(labels, fmt.Sprintf(`%s="%s"`, l[0], escapeLabelValue(l[1])))
+			}
+			fmt.Fprintf(&b, "%s{%s} %s\n", name, strings.Join(labels, ","), formatMetricValue(s.value))
+		}
+	}
+	b.WriteString("# EOF\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// hostLabels returns the labels identifying the host, set on every metric.
+func hostLabels(hostInfo *Info) [][2]string {
+	var model, serial string
+	if hostInfo.Model != nil {
+		model = strings.TrimSpace(hostInfo.Model.Name + " " + hostInfo.Model.SubName)
+	}
+	if hostInfo.SerialNumber != nil {
+		serial = *hostInfo.SerialNumber
+	}
+	return [][2]string{{"hostname", hostInfo.Hostname}, {"model", model}, {"serial", serial}}
+}
+
+// escapeLabelValue escapes a label value as required by OpenMetrics.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatMetricValue(value float64) string {
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return strconv.FormatInt(int64(value), 10)
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// memoryBytes converts the amount of memory to bytes.
+// Like macOS, "GB" means 1024^3 bytes.
+func memoryBytes(memory *Memory) (float64, bool) {
+	multipliers := map[string]float64{"MB": 1 << 20, "GB": 1 << 30, "TB": 1 << 40}
+	multiplier, ok := multipliers[memory.Unit]
+	return float64(memory.Amount) * multiplier, ok
+}
+
+// uptimeSeconds parses the uptime ("3 days, 4 hours").
+func uptimeSeconds(uptime string) (float64, bool) {
+	var days, hours int
+	if _, err := fmt.Sscanf(uptime, "%d days, %d hours", &days, &hours); err != nil {
+		return 0, false
+	}
+	return float64(days*24*3600 + hours*3600), true
+}
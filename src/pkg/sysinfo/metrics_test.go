@@ -0,0 +1,52 @@
+package sysinfo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	serial := "SERIAL"
+	hostInfo := &Info{
+		CachedInfo: CachedInfo{
+			Model:        &Model{Name: "MacBook Pro", SubName: `16"`},
+			SerialNumber: &serial,
+			Gpus:         []Gpu{{Model: "Apple M4 Max", Cores: 40}, {Model: "Apple M4 Max", Cores: 40}},
+		},
+		Hostname: "host",
+		Disk:     &DiskInfo{TotalBytes: 2000000000000, FreeBytes: 1140000000000, SmartStatus: "Verified"},
+		Battery:  &BatteryInfo{StatusPercent: 94, CapacityPercent: 100, Health: "Good"},
+		Uptime:   "1 days, 19 hours",
+		Software: &SoftwareInfo{NumApps: 42, NumBrewFormulae: -1, NumBrewCasks: -1},
+	}
+	errs := Errors{"weather": &ItemError{Item: "weather", Err: ErrTimedOut}}
+
+	var b strings.Builder
+	if err := WriteMetrics(&b, hostInfo, errs); err != nil {
+		t.Fatalf("WriteMetrics failed: %v", err)
+	}
+	output := b.String()
+
+	labels := `hostname="host",model="MacBook Pro 16\"",serial="SERIAL"`
+	for _, expected := range []string{
+		"# TYPE minfo_disk_free_bytes gauge\n",
+		"minfo_disk_free_bytes{" + labels + "} 1140000000000\n",
+		"minfo_disk_smart_verified{" + labels + `,status="Verified"} 1` + "\n",
+		"minfo_gpu_cores{" + labels + `,index="0",gpu="Apple M4 Max"} 40` + "\n",
+		"minfo_gpu_cores{" + labels + `,index="1",gpu="Apple M4 Max"} 40` + "\n",
+		"minfo_battery_charge_ratio{" + labels + "} 0.94\n",
+		"minfo_uptime_seconds{" + labels + "} 154800\n",
+		"minfo_software_apps{" + labels + "} 42\n",
+		"minfo_item_error{" + labels + `,item="weather"} 1` + "\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected metrics to contain %q:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "homebrew") {
+		t.Errorf("Unknown HomeBrew counts should not be written:\n%s", output)
+	}
+	if !strings.HasSuffix(output, "# EOF\n") {
+		t.Errorf("Expected metrics to end with '# EOF'")
+	}
+}
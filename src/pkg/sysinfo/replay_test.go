@@ -0,0 +1,59 @@
+package sysinfo
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
+// Replay the recorded outputs of system_profiler, ioreg, vm_stat... (testdata/replay),
+// and compare the parsed information with testdata/replay.golden.json.
+// Run "go test -run TestReplayGolden -update" to update the golden file.
+func TestReplayGolden(t *testing.T) {
+	opts := Options{
+		Runner: ReplayRunner{Dir: filepath.Join("testdata", "replay")},
+		GOOS:   "darwin",
+	}
+	for _, name := range Items() {
+		if collectors[name].spDataType != "" || collectors[name].fetchDarwin != nil {
+			opts.Items = append(opts.Items, name)
+		}
+	}
+	hostInfo, err := Collect(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	actual, err := json.MarshalIndent(hostInfo, "", "  ")
+	if err != nil {
+		t.Fatalf("Error marshalling JSON: %v", err)
+	}
+	goldenFile := filepath.Join("testdata", "replay.golden.json")
+	if *updateGolden {
+		if err := os.WriteFile(goldenFile, actual, 0644); err != nil {
+			t.Fatalf("Failed to update golden file: %v", err)
+		}
+	}
+	expected, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if string(actual) != string(expected) {
+		t.Errorf("Parsed information differs from %s:\n%s", goldenFile, actual)
+	}
+}
+
+func TestRecordFileName(t *testing.T) {
+	name := recordFileName("/usr/sbin/ioreg", []string{"-arc", "IOPlatformDevice", "-k", "product-name"})
+	if name != "ioreg_arc_IOPlatformDevice_k_product-name" {
+		t.Errorf("Unexpected file name: '%s'", name)
+	}
+	if name := recordFileName("/usr/sbin/ioreg", nil); name != "ioreg" {
+		t.Errorf("Unexpected file name: '%s'", name)
+	}
+}
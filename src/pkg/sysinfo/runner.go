@@ -0,0 +1,93 @@
+package sysinfo
+
+/*
+This file contains the command runners used to call external commands
+(system_profiler, ioreg, brew...).
+Besides running the commands, their outputs can be recorded into a directory
+(RecordRunner), and later replayed from that directory (ReplayRunner), Ex. on a Linux
+machine to test the parsing of the macOS outputs.
+*/
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CommandRunner runs an external command and returns its standard output.
+type CommandRunner interface {
+	Run(ctx context.Context, name string, args ...string) (string, error)
+}
+
+// ExecRunner actually runs the commands.
+// The command is killed when ctx is done.
+type ExecRunner struct{}
+
+func (ExecRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &out
+	err := cmd.Run()
+	return out.String(), err
+}
+
+// RecordRunner runs the commands with Runner, and writes their output into Dir.
+// Each output is written twice: in a file named after the command
+// and its arguments, and in a file named after the command only
+// (i.e. the last recorded output of the command).
+type RecordRunner struct {
+	Dir    string
+	Runner CommandRunner
+}
+
+func (r RecordRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	output, err := r.Runner.Run(ctx, name, args...)
+	if err != nil {
+		return output, err
+	}
+	if err := ensureDirExists(r.Dir); err != nil {
+		return output, err
+	}
+	for _, fileName := range []string{recordFileName(name, args), recordFileName(name, nil)} {
+		if err := os.WriteFile(filepath.Join(r.Dir, fileName), []byte(output), 0644); err != nil {
+			return output, fmt.Errorf("failed to record output: %w", err)
+		}
+	}
+	return output, nil
+}
+
+// ReplayRunner does not run the commands, but returns their output
+// previously recorded into Dir by RecordRunner.
+// If the output of the command with the exact same arguments was not recorded,
+// the last recorded output of the command is used: Ex. the output of
+// system_profiler with all the SPDataTypes can be used for any subset of them.
+type ReplayRunner struct {
+	Dir string
+}
+
+func (r ReplayRunner) Run(_ context.Context, name string, args ...string) (string, error) {
+	for _, fileName := range []string{recordFileName(name, args), recordFileName(name, nil)} {
+		data, err := os.ReadFile(filepath.Join(r.Dir, fileName))
+		if err == nil {
+			return string(data), nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no recorded output for %s in %s", filepath.Base(name), r.Dir)
+}
+
+// recordFileName returns the name of the file holding the output of a command.
+// Ex. "ioreg_arc_IOPlatformDevice_k_product-name"
+func recordFileName(name string, args []string) string {
+	parts := []string{filepath.Base(name)}
+	for _, arg := range args {
+		parts = append(parts, strings.TrimLeft(arg, "-"))
+	}
+	return strings.ReplaceAll(strings.Join(parts, "_"), string(filepath.Separator), "-")
+}
@@ -0,0 +1,304 @@
+package sysinfo
+
+/*
+This file contains the structs used in the package.
+Here are the two main structs
+- Info: contains all the information that can be retrieved.
+- systemProfilerInfo: contains all the information retrieved from system_profiler
+*/
+
+/* ---------- Structs that hold the fetched information ---------- */
+// Information about a display (screen)
+// It is a subset of the Info struct.
+type Display struct {
+	Name             string  `json:"name,omitempty"` // Ex. "Color LCD", "DELL U2723QE"
+	PixelsWidth      int     `json:"pixels_width,omitempty"`
+	PixelsHeight     int     `json:"pixels_height,omitempty"`
+	ResolutionWidth  int     `json:"resolution_width,omitempty"`
+	ResolutionHeight int     `json:"resolution_height,omitempty"`
+	RefreshRateHz    float64 `json:"refresh_rate_hz,omitempty"`
+	ScaleFactor      float64 `json:"scale_factor,omitempty"` // pixels per point of the UI, Ex. 2 for Retina
+	BuiltIn          bool    `json:"built_in,omitempty"`
+	Connection       string  `json:"connection,omitempty"` // Ex. "DisplayPort", "HDMI", "eDP"
+	Main             bool    `json:"main,omitempty"`
+	Mirrored         bool    `json:"mirrored,omitempty"`
+	HDR              bool    `json:"hdr,omitempty"`
+	ProMotion        bool    `json:"promotion,omitempty"` // adaptive refresh rate up to 120 Hz (guessed on macOS)
+}
+
+// Information that can be cached in file.
+// It is a subset of the Info struct.
+type Model struct {
+	Name    string `json:"name,omitempty"`
+	SubName string `json:"sub_name,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Number  string `json:"number,omitempty"`
+}
+type Cpu struct {
+	Model            string `json:"model,omitempty"`
+	Cores            int    `json:"cores,omitempty"`
+	PerformanceCores int    `json:"performance_cores,omitempty"`
+	EfficiencyCores  int    `json:"efficiency_cores,omitempty"`
+}
+type Memory struct {
+	Amount  int    `json:"amount,omitempty"`
+	Unit    string `json:"unit,omitempty"`
+	MemType string `json:"type,omitempty"`
+}
+
+// I use pointers to struct to know if the sub-structs are set or not.
+type CachedInfo struct {
+	Model        *Model  `json:"model,omitempty"`
+	Cpu          *Cpu    `json:"cpu,omitempty"`
+	Gpus         []Gpu   `json:"gpus"` // not omitted when empty, so that no GPU is cached (Ex. headless Linux)
+	Memory       *Memory `json:"memory,omitempty"`
+	SerialNumber *string `json:"serial_number,omitempty"`
+}
+
+// Gpu is a graphics device (see the gpu item).
+type Gpu struct {
+	Vendor string `json:"vendor,omitempty"`
+	Model  string `json:"model,omitempty"`
+	Cores  int    `json:"cores,omitempty"` // Apple Silicon
+	VRAM   string `json:"vram,omitempty"`  // Ex. "8 GB", "1536 MB (shared)"
+	Metal  string `json:"metal,omitempty"` // Metal support (macOS), Ex. "Metal 3"
+}
+
+// MemoryUsage is the current usage of the memory (see the memory_usage item),
+// unlike Memory which is the installed memory.
+type MemoryUsage struct {
+	TotalBytes      uint64  `json:"total_bytes"`
+	UsedBytes       uint64  `json:"used_bytes"`
+	FreeBytes       uint64  `json:"free_bytes"`                 // available without swapping
+	CachedBytes     uint64  `json:"cached_bytes,omitempty"`     // files cache, which can be freed
+	WiredBytes      uint64  `json:"wired_bytes,omitempty"`      // macOS
+	CompressedBytes uint64  `json:"compressed_bytes,omitempty"` // macOS
+	SwapTotalBytes  uint64  `json:"swap_total_bytes,omitempty"`
+	SwapUsedBytes   uint64  `json:"swap_used_bytes,omitempty"`
+	UsedPercent     float64 `json:"used_percent"`
+	Pressure        string  `json:"pressure,omitempty"` // normal, warning or critical
+}
+
+type UserInfo struct {
+	RealName string `json:"real_name,omitempty"`
+	Login    string `json:"login,omitempty"`
+}
+
+type OsInfo struct {
+	System                 string   `json:"system,omitempty"`
+	SystemVersion          string   `json:"system_version,omitempty"`
+	SystemBuild            string   `json:"system_build,omitempty"`
+	SystemVersionCodeNname string   `json:"system_version_code_name,omitempty"`
+	KernelType             string   `json:"kernel_type,omitempty"`
+	KernelVersion          string   `json:"kernel_version,omitempty"`
+	Id                     string   `json:"id,omitempty"`      // ID of /etc/os-release (Linux), Ex. "ubuntu"
+	IdLike                 []string `json:"id_like,omitempty"` // ID_LIKE of /etc/os-release (Linux), Ex. ["debian"]
+}
+
+type DiskInfo struct {
+	TotalTB     float32 `json:"total_tb,omitempty"`
+	FreeTB      float32 `json:"free_tb,omitempty"`
+	TotalBytes  uint64  `json:"total_bytes,omitempty"`
+	FreeBytes   uint64  `json:"free_bytes,omitempty"`
+	SmartStatus string  `json:"smart_status,omitempty"`
+}
+
+// Volume is a mounted volume (see the volumes item).
+type Volume struct {
+	Name        string `json:"name,omitempty"`
+	MountPoint  string `json:"mount_point"`
+	FileSystem  string `json:"file_system,omitempty"`
+	TotalBytes  uint64 `json:"total_bytes,omitempty"`
+	FreeBytes   uint64 `json:"free_bytes,omitempty"`
+	Encrypted   *bool  `json:"encrypted,omitempty"` // nil if unknown
+	SmartStatus string `json:"smart_status,omitempty"`
+}
+
+type BatteryInfo struct {
+	StatusPercent      int            `json:"status_percent,omitempty"`
+	Charging           bool           `json:"charging,omitempty"`
+	CapacityPercent    int            `json:"capacity_percent,omitempty"`
+	Health             string         `json:"health,omitempty"`
+	CycleCount         int            `json:"cycle_count,omitempty"`
+	FullyCharged       bool           `json:"fully_charged,omitempty"`
+	AtWarnLevel        bool           `json:"at_warn_level,omitempty"`         // the charge is low
+	TimeToEmptyMinutes int            `json:"time_to_empty_minutes,omitempty"` // estimated, when discharging
+	TimeToFullMinutes  int            `json:"time_to_full_minutes,omitempty"`  // estimated, when charging
+	ACAdapter          *ACAdapterInfo `json:"ac_adapter,omitempty"`
+}
+
+type ACAdapterInfo struct {
+	Connected bool `json:"connected"`
+	Watts     int  `json:"watts,omitempty"`
+}
+
+type SoftwareInfo struct {
+	NumApps         int `json:"num_apps,omitempty"`
+	NumBrewFormulae int `json:"num_homebrew_formulae,omitempty"`
+	NumBrewCasks    int `json:"num_homebrew_casks,omitempty"`
+}
+
+type PublicIpInfo struct {
+	IP          string  `json:"query,omitempty"`
+	Country     string  `json:"country,omitempty"`
+	CountryCode string  `json:"countryCode,omitempty"`
+	City        string  `json:"city,omitempty"`
+	State       string  `json:"regionName,omitempty"`
+	Latitude    float64 `json:"lat,omitempty"`
+	Longitude   float64 `json:"lon,omitempty"`
+}
+
+// Info contains all the information that can be retrieved.
+// Note: I use pointer to struct, so that when the user requests
+// JSON output, the output will not contain empty fields.
+type Info struct {
+	CachedInfo
+	User            *UserInfo     `json:"user,omitempty"`
+	Hostname        string        `json:"hostname,omitempty"`
+	Os              *OsInfo       `json:"os,omitempty"`
+	SystemIntegrity string        `json:"system_integrity,omitempty"`
+	Disk            *DiskInfo     `json:"disk,omitempty"`
+	Volumes         []Volume      `json:"volumes,omitempty"`
+	MemoryUsage     *MemoryUsage  `json:"memory_usage,omitempty"`
+	Battery         *BatteryInfo  `json:"battery,omitempty"`
+	Displays        []Display     `json:"displays,omitempty"`
+	Software        *SoftwareInfo `json:"software,omitempty"`
+	Terminal        string        `json:"terminal,omitempty"`
+	Uptime          string        `json:"uptime,omitempty"`
+	Datetime        string        `json:"datetime,omitempty"`
+	PublicIp        *PublicIpInfo `json:"public_ip,omitempty"`
+	Weather         *Weather      `json:"weather,omitempty"`
+}
+
+type Weather struct {
+	Latitude            float64 `json:"latitude,omitempty"`
+	Longitude           float64 `json:"longitude,omitempty"`
+	LocationName        string  `json:"location_name,omitempty"`
+	LocationState       string  `json:"location_state,omitempty"`
+	LocationCountryCode string  `json:"location_country_code,omitempty"`
+	LocationCountry     string  `json:"location_country,omitempty"`
+	CurrentWeather      string  `json:"current_weather,omitempty"`
+	Temperature         float64 `json:"temperature,omitempty"`
+	FeelsLike           float64 `json:"feels_like,omitempty"`
+	TempUnit            string  `json:"temp_unit,omitempty"`
+	WindSpeed           float64 `json:"wind_speed,omitempty"`
+	WindGusts           float64 `json:"wind_gusts,omitempty"`
+	WindUnit            string  `json:"wind_unit,omitempty"`
+	WindDirection       int     `json:"wind_direction,omitempty"`
+}
+
+/* ---------- Structs for system_profiler parsing ---------- */
+
+type HardwareInfo struct {
+	MachineName  string      `json:"machine_name"`
+	MachineModel string      `json:"machine_model"`
+	ModelNumber  string      `json:"model_number"`
+	NumProc      interface{} `json:"number_processors"` // Can be a string (Apple Silicon) or an int (Intel)
+	ChipType     string      `json:"-"`                 // Common field to store "chip_type" (Apple Silicon) or "cpu_type" (Intel)
+	SerialNumber string      `json:"serial_number"`
+}
+
+type systemProfilerInfo struct {
+	// One entry per GPU, with the displays connected to it
+	Displays []struct {
+		Name        string `json:"_name"`
+		NumCores    string `json:"sppci_cores"`
+		Model       string `json:"sppci_model"`
+		Vendor      string `json:"spdisplays_vendor"`
+		VRAM        string `json:"spdisplays_vram"`
+		VRAMShared  string `json:"spdisplays_vram_shared"`
+		Metal       string `json:"spdisplays_metal"`               // older macOS versions
+		MetalFamily string `json:"spdisplays_mtlgpufamilysupport"` // Ex. "spdisplays_metal3"
+		Ndrvs       []struct {
+			Name           string `json:"_name"`
+			Pixels         string `json:"_spdisplays_pixels"`
+			Resolution     string `json:"_spdisplays_resolution"`
+			ConnectionType string `json:"spdisplays_connection_type"` // Ex. "spdisplays_internal"
+			DisplayType    string `json:"spdisplays_display_type"`    // Ex. "spdisplays_built-in-liquid-retina-xdr"
+			Main           string `json:"spdisplays_main"`
+			Mirror         string `json:"spdisplays_mirror"`
+			Online         string `json:"spdisplays_online"`
+		} `json:"spdisplays_ndrvs"`
+	} `json:"SPDisplaysDataType"`
+
+	Software []struct {
+		UserName        string `json:"user_name"`
+		HostName        string `json:"local_host_name"`
+		OsVersion       string `json:"os_version"`
+		Uptime          string `json:"uptime"`
+		Kernel          string `json:"kernel_version"`
+		SystemIntegrity string `json:"system_integrity"`
+	} `json:"SPSoftwareDataType"`
+
+	Hardware []HardwareInfo `json:"SPHardwareDataType"`
+
+	// The battery, then the AC charger (sppower_ac_charger_information)
+	Power []struct {
+		Name             string      `json:"_name"`
+		ChargerConnected string      `json:"sppower_battery_charger_connected"`
+		ChargerWatts     interface{} `json:"sppower_ac_charger_watts"` // Can be a string or an int
+
+		BatteryChargeInfo struct {
+			StateOfCharge int    `json:"sppower_battery_state_of_charge"`
+			AtWarnLevel   string `json:"sppower_battery_at_warn_level"`
+			FullyCharged  string `json:"sppower_battery_fully_charged"`
+			IsCharging    string `json:"sppower_battery_is_charging"`
+		} `json:"sppower_battery_charge_info"`
+
+		BatteryHealthInfo struct {
+			CycleCount  int    `json:"sppower_battery_cycle_count"`
+			MaxCapacity string `json:"sppower_battery_health_maximum_capacity"`
+			Health      string `json:"sppower_battery_health"`
+		} `json:"sppower_battery_health_info"`
+	} `json:"SPPowerDataType"`
+
+	Memory []interface{} `json:"SPMemoryDataType"`
+
+	Storage []struct {
+		Name          string `json:"_name"`
+		BsdName       string `json:"bsd_name"`
+		FileSystem    string `json:"file_system"`
+		FreeSpaceByte int    `json:"free_space_in_bytes"`
+		SizeByte      int    `json:"size_in_bytes"`
+		MountPoint    string `json:"mount_point"`
+		PhyDrive      struct {
+			SmartStatus string `json:"smart_status"`
+		} `json:"physical_drive"`
+	} `json:"SPStorageDataType"`
+}
+
+type openMeteo struct {
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	Elevation    float64 `json:"elevation"`
+	CurrentUnits struct {
+		Time             string `json:"time"`
+		Interval         string `json:"interval"`
+		Temperature2m    string `json:"temperature_2m"`
+		WeatherCode      string `json:"weather_code"`
+		WindSpeed10m     string `json:"wind_speed_10m"`
+		WindDirection10m string `json:"wind_direction_10m"`
+		WindGusts10m     string `json:"wind_gusts_10m"`
+	} `json:"current_units"`
+	Current struct {
+		Time                string  `json:"time"`
+		Interval            int     `json:"interval"`
+		Temperature2m       float64 `json:"temperature_2m"`
+		ApparentTemperature float64 `json:"apparent_temperature"`
+		WeatherCode         int     `json:"weather_code"`
+		WindSpeed10m        float64 `json:"wind_speed_10m"`
+		WindDirection10m    int     `json:"wind_direction_10m"`
+		WindGusts10m        float64 `json:"wind_gusts_10m"`
+	} `json:"current"`
+}
+
+type openMeteoGeo struct {
+	Results []struct {
+		Latitude    float64 `json:"latitude"`
+		Longitude   float64 `json:"longitude"`
+		Name        string  `json:"name"`
+		CountryCode string  `json:"country_code"`
+		Country     string  `json:"country"`
+		Admin1      string  `json:"admin1"` // State (US), Canton (CH), Region (FR), etc...
+	} `json:"results"`
+}
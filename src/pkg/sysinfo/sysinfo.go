@@ -0,0 +1,231 @@
+// Package sysinfo collects information about the host system
+// (model, CPU, memory, OS, disk, battery, displays, public IP, weather...),
+// on macOS (system_profiler, ioreg) and Linux (/proc, /sys, /etc/os-release).
+//
+// It is the library behind the minfo command:
+//
+//	info, err := sysinfo.Collect(ctx, sysinfo.Options{Items: []string{"cpu", "memory"}})
+//	var errs sysinfo.Errors
+//	if errors.As(err, &errs) {
+//		// Some items could not be fetched, the others are set in info.
+//	}
+package sysinfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is the overall time given to Collect when Options.Timeout is not set.
+const DefaultTimeout = 10 * time.Second
+
+// DefaultItems are the items collected when Options.Items is not set.
+var DefaultItems = []string{
+	"user",
+	"hostname",
+	"os",
+	"system_integrity",
+	"serial_number",
+	"model",
+	"cpu",
+	"gpu",
+	"memory",
+	"disk",
+	"battery",
+	"display",
+	"terminal",
+	"software",
+	"public_ip",
+	"uptime",
+	"datetime",
+}
+
+// Options of Collect. The zero value collects the DefaultItems,
+// without any cache file.
+type Options struct {
+	// Items to collect (see Items). Default: DefaultItems.
+	Items []string
+	// Overall time given to collect the information. Default: DefaultTimeout.
+	Timeout time.Duration
+	// Time given to some items, by name (bounded by Timeout).
+	ItemTimeouts map[string]time.Duration
+
+	// Whether the information which does not change (model, CPU, GPU,
+	// memory, serial number) is read from CacheFile.
+	// The cache file is written if it does not exist yet, or if it
+	// lacks some of the requested items.
+	Cache     bool
+	CacheFile string
+	// Fetch again the information stored in the cache file and rewrite it
+	// (and do not use the weather cache file).
+	RefreshCache bool
+
+	Weather WeatherOptions
+	// Volumes listed by the volumes item. Default: all the mounted volumes.
+	Volumes VolumeFilter
+	// The weather is cached for 15 minutes in this file. Default: no cache.
+	WeatherCacheFile string
+
+	// Runner used to call the external commands (system_profiler, ioreg, brew...).
+	// Default: ExecRunner.
+	Runner CommandRunner
+	// Operating system whose commands/files are used, Ex. "darwin" to replay
+	// on Linux the commands recorded on a Mac (see ReplayRunner).
+	// Default: runtime.GOOS.
+	GOOS string
+}
+
+// WeatherOptions define the location and the units of the weather.
+// If neither LocationName nor Latitude/Longitude are set,
+// the location of the public IP is used.
+type WeatherOptions struct {
+	Latitude  *float64
+	Longitude *float64
+	// Location name, state (optional) and country, in English.
+	LocationName    string
+	LocationState   string
+	LocationCountry string
+	Units           string // "metric" (default) or "imperial"
+	Lang            string // "en" (default) or "fr"
+}
+
+// VolumeFilter selects the volumes listed by the volumes item.
+// A volume is listed if it matches the Include lists (when they are set)
+// and none of the Exclude lists. Mount points are matched with globs
+// (see path.Match, Ex. "/Volumes/*"), file systems are compared case-insensitively.
+type VolumeFilter struct {
+	IncludeMountPoints []string
+	ExcludeMountPoints []string
+	IncludeFileSystems []string
+	ExcludeFileSystems []string
+}
+
+// matches returns whether the volume is to be listed.
+func (f VolumeFilter) matches(volume Volume) bool {
+	matchMountPoint := func(patterns []string) bool {
+		return slices.ContainsFunc(patterns, func(pattern string) bool {
+			matched, _ := path.Match(pattern, volume.MountPoint)
+			return matched
+		})
+	}
+	matchFileSystem := func(fileSystems []string) bool {
+		return slices.ContainsFunc(fileSystems, func(fileSystem string) bool {
+			return strings.EqualFold(fileSystem, volume.FileSystem)
+		})
+	}
+	switch {
+	case len(f.IncludeMountPoints) > 0 && !matchMountPoint(f.IncludeMountPoints),
+		len(f.IncludeFileSystems) > 0 && !matchFileSystem(f.IncludeFileSystems):
+		return false
+	}
+	return !matchMountPoint(f.ExcludeMountPoints) && !matchFileSystem(f.ExcludeFileSystems)
+}
+
+// itemTimeout returns the time given to an item to fetch its information:
+// its own timeout if defined, the overall timeout otherwise.
+func (opts *Options) itemTimeout(name string) time.Duration {
+	if timeout, ok := opts.ItemTimeouts[name]; ok {
+		return timeout
+	}
+	return opts.Timeout
+}
+
+// withDefaults returns a copy of opts where the unset options have their default value.
+func (opts Options) withDefaults() Options {
+	if opts.Items == nil {
+		opts.Items = DefaultItems
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Runner == nil {
+		opts.Runner = ExecRunner{}
+	}
+	if opts.GOOS == "" {
+		opts.GOOS = runtime.GOOS
+	}
+	if opts.Weather.Units == "" {
+		opts.Weather.Units = "metric"
+	}
+	if opts.Weather.Lang == "" {
+		opts.Weather.Lang = "en"
+	}
+	return opts
+}
+
+// Collect fetches concurrently the information of the requested items.
+// When some items cannot be fetched, the information of the other items
+// is still returned, along with an Errors error listing the failed items.
+// Any other error (Ex. invalid item, unreadable cache file) is returned with a nil Info.
+func Collect(ctx context.Context, opts Options) (*Info, error) {
+	opts = opts.withDefaults()
+	for _, item := range opts.Items {
+		if _, ok := collectors[item]; !ok {
+			return nil, fmt.Errorf("invalid item: %s", item)
+		}
+	}
+
+	// Overall deadline to fetch the information
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	hostInfo := &Info{}
+	fetchErrors := Errors{}
+
+	/* ---------- Deal with cache ---------- */
+	// We cache some data which are not going to change:
+	// - computer model, CPU, GPU, memory, and serial number.
+	// If the cache file does not exist yet, we fetch all the data
+	// that can be cached (even if not requested), and write it.
+	refreshCache := opts.Cache && opts.RefreshCache
+	if opts.Cache && !refreshCache {
+		if err := readCacheFile(opts.CacheFile, hostInfo); err != nil {
+			if !errors.Is(err, os.ErrNotExist) && err != errEmptyCache {
+				return nil, fmt.Errorf("error reading cache file: %w", err)
+			}
+			// no cache file (or empty) --> Must populate it, i.e. like RefreshCache.
+			refreshCache = true
+		} else if !cachedItemsComplete(opts.Items, hostInfo) {
+			refreshCache = true
+		}
+	}
+	if refreshCache {
+		errs, err := populateCache(ctx, &opts, hostInfo)
+		if err != nil {
+			return nil, fmt.Errorf("error while refreshing cache: %w", err)
+		}
+		maps.Copy(fetchErrors, errs)
+	}
+
+	/* ---------- Fetch information ---------- */
+	// Cached items have already been read from (or written to) the cache file.
+	var toFetch []*collector
+	for _, item := range opts.Items {
+		c := collectors[item]
+		if c.cached && opts.Cache {
+			continue
+		}
+		toFetch = append(toFetch, c)
+	}
+	src := newSources(ctx, &opts, toFetch)
+	maps.Copy(fetchErrors, fetchCollectors(ctx, src, toFetch, hostInfo))
+
+	// We might have read from the cache some information that was not requested.
+	for _, name := range Items() {
+		if !slices.Contains(opts.Items, name) {
+			clearField(collectors[name], hostInfo)
+			delete(fetchErrors, name)
+		}
+	}
+	if len(fetchErrors) > 0 {
+		return hostInfo, fetchErrors
+	}
+	return hostInfo, nil
+}
@@ -0,0 +1,24 @@
+package sysinfo
+
+import "testing"
+
+func TestVolumeFilter(t *testing.T) {
+	data := Volume{MountPoint: "/System/Volumes/Data", FileSystem: "APFS"}
+	external := Volume{MountPoint: "/Volumes/T7", FileSystem: "ExFAT"}
+	tests := []struct {
+		filter   VolumeFilter
+		expected [2]bool // data, external
+	}{
+		{VolumeFilter{}, [2]bool{true, true}},
+		{VolumeFilter{IncludeMountPoints: []string{"/Volumes/*"}}, [2]bool{false, true}},
+		{VolumeFilter{ExcludeMountPoints: []string{"/System/Volumes/*"}}, [2]bool{false, true}},
+		{VolumeFilter{IncludeFileSystems: []string{"apfs"}}, [2]bool{true, false}},
+		{VolumeFilter{IncludeMountPoints: []string{"/Volumes/*"}, ExcludeFileSystems: []string{"exfat"}}, [2]bool{false, false}},
+	}
+	for _, test := range tests {
+		actual := [2]bool{test.filter.matches(data), test.filter.matches(external)}
+		if actual != test.expected {
+			t.Errorf("%+v: expected %v, got %v", test.filter, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package sysinfo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// capitalizeFirstLetter capitalizes the first letter of a string
+func capitalizeFirstLetter(s string) string {
+	if len(s) == 0 {
+		return s // Return an empty string if input is empty
+	}
+	return strings.ToUpper(string(s[0])) + s[1:]
+}
+
+// This function replicates the functionality of the "which" command
+func which(command string) (string, error) {
+	pathEnv := os.Getenv("PATH")
+	if pathEnv == "" {
+		return "", errors.New("PATH environment variable is empty")
+	}
+	paths := filepath.SplitList(pathEnv)
+
+	for _, dir := range paths {
+		fullPath := filepath.Join(dir, command)
+
+		if fileInfo, err := os.Stat(fullPath); err == nil {
+			if !fileInfo.IsDir() && (fileInfo.Mode()&0111 != 0) { // Check for executable bit
+				return fullPath, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%s: command not found", command)
+}
+
+func ensureDirExists(dirPath string) error {
+	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+		err := os.MkdirAll(dirPath, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("failed to create directory: %v", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to check directory: %v", err)
+	}
+	return nil
+}
+
+// isFileOlderThan checks if a file's modification time is older than the given duration.
+func isFileOlderThan(filePath string, duration time.Duration) (bool, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to get file info: %v", err)
+	}
+	modTime := info.ModTime()
+
+	return time.Since(modTime) > duration, nil
+}
+
+// countNonEmptyLines returns the number of non-empty trimmed lines in the provided string.
+func countNonEmptyLines(input string) int {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	count := 0
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		count++
+	}
+	return count
+}
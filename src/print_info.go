@@ -1,345 +1,340 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"strings"
 	"unicode/utf8"
 
-	"github.com/jwalton/go-supportscolor"
+	"minfo/pkg/sysinfo"
 )
 
 // helper to create a line of information
-func createInfoLine(requestedItem, info string) []string {
-	var realTitle string
+func createInfoLine(line infoLine) []string {
+	realTitle := line.Title
 	if config.DisplayNerdSymbols != nil && *config.DisplayNerdSymbols {
-		realTitle = fmt.Sprintf("%s %s", availableItems[requestedItem].Nerd, availableItems[requestedItem].Title)
-	} else {
-		realTitle = availableItems[requestedItem].Title
+		realTitle = fmt.Sprintf("%s %s", line.Nerd, line.Title)
 	}
-	//return []string{colorCyan, availableItems[requestedItem].Title, colorNormal, info}
-	return []string{colorCyan, realTitle, colorNormal, info}
+	return []string{colorTitle, realTitle, colorNormal, line.Value}
 }
 
 // Each info line gets a Title and actual information
 // This function calculates the padding size needed to align
-// the information of all the lines (i.e. calulate the longest title).
+// the information of all the lines (i.e. calulate the longest title),
+// including the separator between the title and the value (see LayoutConfig.Separator).
 func getPaddingSize(infoLines [][]string) int {
 	paddingSize := 0
 	for _, i := range infoLines {
-		if len(i[1]) > paddingSize {
+		if len(i) > 1 && len(i[1]) > paddingSize {
 			paddingSize = len(i[1])
 		}
 	}
-	return paddingSize + 1
+	// No title at all (Ex. custom format)
+	if paddingSize == 0 {
+		return 0
+	}
+	return paddingSize + visibleLength(config.Layout.Separator)
 }
 
-// This function ensures the logo is padding (i.e. suffixed) with spaces,
-// so that all the lines of the logo have the same lenght.
+// This function ensures the lines (of the logo, or of the information
+// displayed on the left of the logo) are padded (i.e. suffixed) with spaces,
+// so that all the lines have the same lenght.
 // We have to deal with ANSI codes .... (not taken into account in line lenght)
-// It returns the length of the longest line of the logo.
-func padLogoLines(logoLines *[]string) int {
+// It returns the length of the longest line.
+func padLines(lines *[]string) int {
 	// Find the longest line
 	maxLen := 0
-	for _, line := range *logoLines {
+	for _, line := range *lines {
 		lenLine := utf8.RuneCountInString(reANSI.ReplaceAllString(line, ""))
 		if lenLine > maxLen {
 			maxLen = lenLine
 		}
 	}
 
-	for i, line := range *logoLines {
+	for i, line := range *lines {
 		lenLine := utf8.RuneCountInString(reANSI.ReplaceAllString(line, ""))
 		if lenLine < maxLen {
-			(*logoLines)[i] = fmt.Sprintf("%s%-*s", line, maxLen-lenLine, " ")
+			(*lines)[i] = fmt.Sprintf("%s%-*s", line, maxLen-lenLine, " ")
 		}
 	}
 	return maxLen
 }
 
-// Print the information in a human-readable format
-func printInfo(hostInfo *info) error {
-	var output strings.Builder
+// centerLines vertically centers lines in totalLines lines, adding lines of width spaces
+// at the top and the bottom. It returns the lines, and the index of the first original line.
+func centerLines(lines []string, totalLines, width int) ([]string, int) {
+	topPadding := (totalLines - len(lines)) / 2
+	emptyLine := strings.Repeat(" ", width)
+	centered := make([]string, 0, totalLines)
+	for range topPadding {
+		centered = append(centered, emptyLine)
+	}
+	centered = append(centered, lines...)
+	for len(centered) < totalLines {
+		centered = append(centered, emptyLine)
+	}
+	return centered, topPadding
+}
 
-	if supportscolor.Stdout().Has256 || supportscolor.Stderr().Has16m {
-		colorCyan = "\u001B[38;5;039m"
-	} else {
-		colorCyan = "\u001B[00;36m"
+// formatInfoLine formats a line of information (see createInfoLines),
+// the title and the separator taking padding characters.
+func formatInfoLine(line []string, padding int) string {
+	if len(line) == 1 {
+		return line[0]
 	}
+	// The colors within the value (Ex. warning) go back to the value color.
+	value := line[3]
+	if colorValue != "" && value != "" {
+		value = colorValue + strings.ReplaceAll(value, colorNormal, colorNormal+colorValue) + colorNormal
+	}
+	if padding == 0 {
+		return line[0] + line[1] + line[2] + value
+	}
+	// No separator on the lines without title (Ex. wrapped values)
+	separator := config.Layout.Separator
+	separatorWidth := visibleLength(separator)
+	if line[1] == "" {
+		separator = strings.Repeat(" ", separatorWidth)
+	} else if colorSeparator != "" && strings.TrimSpace(separator) != "" {
+		separator = colorSeparator + separator + colorNormal
+	}
+	titleWidth := padding - separatorWidth
+	if config.Layout.TitleAlign == alignRight {
+		return fmt.Sprintf("%s%*s%s%s%s", line[0], titleWidth, line[1], line[2], separator, value)
+	}
+	return fmt.Sprintf("%s%-*s%s%s%s", line[0], titleWidth, line[1], line[2], separator, value)
+}
 
-	// Each item of infoLines is a slice of strings representing a line
-	// of information. Each line contains:
-	// - Color code for the Item title
-	// - Title
-	// - Color code for the information
-	// - infomation
+// Items displayed in the header (see LayoutConfig.Header)
+var headerItems = []string{"user", "hostname"}
+
+// createHeaderLines returns the header of the information (see LayoutConfig.Header):
+// user@hostname, underlined.
+func createHeaderLines(hostInfo *sysinfo.Info) [][]string {
+	if hostInfo.User == nil || hostInfo.User.Login == "" || hostInfo.Hostname == "" {
+		return nil
+	}
+	header := hostInfo.User.Login + "@" + hostInfo.Hostname
+	return [][]string{
+		{colorBold + colorTitle + header + colorNormal},
+		{colorSeparator + strings.Repeat("-", utf8.RuneCountInString(header)) + colorNormal},
+	}
+}
+
+// createInfoLines creates the lines of information to display,
+// either with the format template (see Config.Format), or item by item.
+// Items which could not be fetched (see fetchErrors) are displayed
+// as "unavailable (reason)", or "timed out".
+// Each item of infoLines is a slice of strings representing a line
+// of information. Each line contains:
+// - Color code for the Item title
+// - Title
+// - Color code for the information
+// - infomation
+// The lines of the header and of the section titles (see LayoutConfig) contain only
+// the text to display, without title.
+func createInfoLines(hostInfo *sysinfo.Info, fetchErrors sysinfo.Errors) ([][]string, error) {
 	infoLines := [][]string{}
+	if config.Layout.Header {
+		infoLines = append(infoLines, createHeaderLines(hostInfo)...)
+	}
 
-	/* ---------- Create the information lines ---------- */
-	for _, requestedItem := range config.Items {
-		switch requestedItem {
-		case "user":
-			infoLines = append(infoLines, createInfoLine(requestedItem,
-				fmt.Sprintf("%s (%s)", hostInfo.User.RealName, hostInfo.User.Login),
-			))
-		case "hostname":
-			infoLines = append(infoLines, createInfoLine(requestedItem, hostInfo.Hostname))
-		case "os":
-			infoLines = append(infoLines, createInfoLine(requestedItem,
-				fmt.Sprintf("%s %s %s (%s) %s %s",
-					hostInfo.Os.System,
-					hostInfo.Os.SystemVersionCodeNname,
-					hostInfo.Os.SystemVersion,
-					hostInfo.Os.SystemBuild,
-					hostInfo.Os.KernelType,
-					hostInfo.Os.KernelVersion,
-				),
-			))
-		case "system_integrity":
-			hostInfo.SystemIntegrity = capitalizeFirstLetter(
-				strings.TrimPrefix(hostInfo.SystemIntegrity, "integrity_"),
-			)
-			infoLines = append(infoLines, createInfoLine(requestedItem, hostInfo.SystemIntegrity))
-		case "serial_number":
-			infoLines = append(infoLines, createInfoLine(requestedItem, *hostInfo.SerialNumber))
-		case "model":
-			infoLines = append(infoLines, createInfoLine(requestedItem,
-				fmt.Sprintf("%s %s (%s) %s",
-					hostInfo.Model.Name,
-					hostInfo.Model.SubName,
-					hostInfo.Model.Date,
-					hostInfo.Model.Number,
-				),
-			))
-		case "cpu":
-			var cpuCoreInfo string
-			if strings.HasPrefix(hostInfo.Cpu.Model, "Apple") {
-				cpuCoreInfo = fmt.Sprintf("%s %d cores (%d P and %d E)",
-					hostInfo.Cpu.Model,
-					hostInfo.Cpu.Cores,
-					hostInfo.Cpu.PerformanceCores,
-					hostInfo.Cpu.EfficiencyCores,
-				)
-			} else {
-				// Intel CPU: The model also includes the number of cores
-				// (Ex: "6-Core Intel Core i7")
-				cpuCoreInfo = fmt.Sprintf("%s", hostInfo.Cpu.Model)
+	if config.Format != nil {
+		tmpl, err := newTemplate("format", *config.Format)
+		if err != nil {
+			return nil, err
+		}
+		lines, err := executeTemplate(tmpl, hostInfo)
+		if err != nil {
+			return nil, fmt.Errorf("error in format: %w", err)
+		}
+		for _, line := range lines {
+			infoLines = append(infoLines, []string{"", "", "", line})
+		}
+		return infoLines, nil
+	}
+
+	itemTemplates, err := parseItemFormats(config.ItemFormats)
+	if err != nil {
+		return nil, err
+	}
+	// The items are grouped by section, with the section titles, if requested.
+	requestedItems := config.Items
+	if config.Layout.Sections {
+		requestedItems = nil
+		for _, section := range sections {
+			for _, name := range config.Items {
+				if items[name].section == section {
+					requestedItems = append(requestedItems, name)
+				}
 			}
-			infoLines = append(infoLines, createInfoLine(requestedItem, cpuCoreInfo))
-		case "gpu":
-			infoLines = append(infoLines, createInfoLine(requestedItem,
-				fmt.Sprintf("%d cores", *hostInfo.GpuCores),
-			))
-		case "memory":
-			infoLines = append(infoLines, createInfoLine(requestedItem,
-				fmt.Sprintf("%d %s %s",
-					hostInfo.Memory.Amount,
-					hostInfo.Memory.Unit,
-					hostInfo.Memory.MemType,
-				),
-			))
-		case "disk":
-			infoLines = append(infoLines, createInfoLine(requestedItem,
-				fmt.Sprintf("%.2f TB (%.2f TB available)",
-					hostInfo.Disk.TotalTB,
-					hostInfo.Disk.FreeTB,
-				),
-			))
-			tmp := createInfoLine(requestedItem, hostInfo.Disk.SmartStatus)
-			tmp[1] = fmt.Sprintf("%s SMART", tmp[1])
-			infoLines = append(infoLines, tmp)
-		case "battery":
-			var charging string
-			if hostInfo.Battery.Charging {
-				charging = "(charging)"
-			} else {
-				charging = "(discharging)"
+		}
+	}
+	// The lines of the values which reach their thresholds are marked.
+	levels := checkLevels(hostInfo)
+	currentSection := ""
+	for _, requestedItem := range requestedItems {
+		it := items[requestedItem]
+		if config.Layout.Sections && it.section != currentSection {
+			if currentSection != "" {
+				infoLines = append(infoLines, []string{""})
 			}
-			infoLines = append(infoLines, createInfoLine(requestedItem,
-				fmt.Sprintf("%d%% %s | %d%% capacity",
-					hostInfo.Battery.StatusPercent,
-					charging,
-					hostInfo.Battery.CapacityPercent,
-				),
-			))
-			tmp := createInfoLine(requestedItem, hostInfo.Battery.Health)
-			tmp[1] = fmt.Sprintf("%s health", tmp[1])
-			infoLines = append(infoLines, tmp)
-		case "display":
-			var displayInfo []string
-			for _, display := range hostInfo.Displays {
-				displayInfo = append(displayInfo, fmt.Sprintf("%d x %d | %d x %d @ %.0f Hz",
-					display.PixelsWidth,
-					display.PixelsHeight,
-					display.ResolutionWidth,
-					display.ResolutionHeight,
-					display.RefreshRateHz,
-				))
+			currentSection = it.section
+			infoLines = append(infoLines, []string{colorBold + currentSection + colorNormal})
+		}
+		if err, ok := fetchErrors[requestedItem]; ok {
+			reason := sysinfo.ErrTimedOut.Error()
+			if !errors.Is(err, sysinfo.ErrTimedOut) {
+				reason = fmt.Sprintf("unavailable (%s)", err.Reason())
 			}
-			displayLines := make([][]string, len(hostInfo.Displays))
-			for i, d := range displayInfo {
-				displayLines[i] = createInfoLine(requestedItem, d)
-				displayLines[i][1] = fmt.Sprintf("%s #%d", displayLines[i][1], i+1)
+			infoLines = append(infoLines, createInfoLine(infoLine{
+				Nerd:  it.nerd,
+				Title: it.title,
+				Value: colorDim + reason + colorNormal,
+			}))
+			continue
+		}
+		// The item's template replaces its default lines:
+		// only the first line of the template's output gets the title.
+		// Its lines are not marked, as the checked values can be anywhere in them.
+		if tmpl, ok := itemTemplates[requestedItem]; ok {
+			lines, err := executeTemplate(tmpl, hostInfo)
+			if err != nil {
+				return nil, fmt.Errorf("error in format of %s: %w", requestedItem, err)
 			}
-			infoLines = append(infoLines, displayLines...)
-		case "terminal":
-			infoLines = append(infoLines, createInfoLine(requestedItem, hostInfo.Terminal))
-		case "software":
-			infoLines = append(infoLines, createInfoLine(requestedItem,
-				fmt.Sprintf("%d Apps | %d Formulae | %d Casks",
-					hostInfo.Software.NumApps,
-					hostInfo.Software.NumBrewFormulae,
-					hostInfo.Software.NumBrewCasks,
-				),
-			))
-		case "public_ip":
-			if hostInfo.PublicIp != nil {
-				// Case we have a "Unknown" country (any error in function getPublicIpInfo)
-				if len(hostInfo.PublicIp.Country) == 0 {
-					infoLines = append(infoLines, createInfoLine(requestedItem, hostInfo.PublicIp.IP))
-				} else {
-					infoLines = append(infoLines, createInfoLine(requestedItem,
-						fmt.Sprintf("%s (%s)",
-							hostInfo.PublicIp.IP,
-							hostInfo.PublicIp.Country,
-						),
-					))
+			for i, value := range lines {
+				line := it.line(value)
+				if i > 0 {
+					line.Nerd, line.Title = "", ""
 				}
-			} else {
-				infoLines = append(infoLines, createInfoLine(requestedItem, "Unknown"))
+				infoLines = append(infoLines, createInfoLine(line))
 			}
-		case "uptime":
-			infoLines = append(infoLines, createInfoLine(requestedItem, hostInfo.Uptime))
-		case "datetime":
-			infoLines = append(infoLines, createInfoLine(requestedItem, hostInfo.Datetime))
-		case "weather":
-			var location string
+			continue
+		}
+		for i, line := range it.lines(it, hostInfo) {
+			infoLines = append(infoLines, createInfoLine(markLine(line, levels[requestedItem][i])))
+		}
+	}
+	return infoLines, nil
+}
 
-			if hostInfo.Weather.LocationName != "" {
-				location = fmt.Sprintf("%s, %s", hostInfo.Weather.LocationName, hostInfo.Weather.LocationCountryCode)
-			} else if hostInfo.PublicIp != nil {
-				location = fmt.Sprintf("%s, %s", hostInfo.PublicIp.City, hostInfo.PublicIp.CountryCode)
-			} else {
-				location = fmt.Sprintf("(%f, %f)", hostInfo.Weather.Latitude, hostInfo.Weather.Longitude)
+// renderInfo returns the information in a human-readable format
+// (with the logo, if enabled), fitting the width of the terminal (see layout.go).
+func renderInfo(hostInfo *sysinfo.Info, fetchErrors sysinfo.Errors) (string, error) {
+	var output strings.Builder
+
+	level := terminalColorLevel()
+	if err := applyTheme(config.Theme, level); err != nil {
+		return "", err
+	}
+
+	infoLines, err := createInfoLines(hostInfo, fetchErrors)
+	if err != nil {
+		return "", err
+	}
+
+	/* ---------- Load the logo ---------- */
+	var logoLines []string
+	var image *imageLogo // the image logo is displayed over placeholder lines, if the terminal supports it
+	lenLogoLine := 0
+	if *config.DisplayLogo {
+		if config.ImageLogo != nil {
+			if image, err = loadImageLogo(config.ImageLogo); err != nil {
+				return "", err
 			}
-			infoLines = append(infoLines, createInfoLine(requestedItem,
-				fmt.Sprintf("%s: %s",
-					location,
-					hostInfo.Weather.CurrentWeather,
-				),
-			))
-			tmp := createInfoLine(requestedItem,
-				fmt.Sprintf(
-					"%s (%s) %s | %s %.0f (%.0f) %s",
-					formatFloat(roundToNearestHalf(hostInfo.Weather.Temperature)),
-					formatFloat(roundToNearestHalf(hostInfo.Weather.FeelsLike)),
-					hostInfo.Weather.TempUnit,
-					windArrow(hostInfo.Weather.WindDirection),
-					hostInfo.Weather.WindSpeed,
-					hostInfo.Weather.WindGusts,
-					hostInfo.Weather.WindUnit,
-				),
-			)
-			if config.DisplayNerdSymbols != nil && *config.DisplayNerdSymbols {
-				tmp[1] = " Temp. | Wind"
-			} else {
-				tmp[1] = "Temp. | Wind"
+		}
+		if image != nil {
+			logoLines = image.placeholder()
+		} else {
+			logoName := *config.Logo
+			if logoName == logoAuto {
+				logoName = autoLogo(hostInfo)
+			}
+			if logoLines, err = loadLogo(logoName, level); err != nil {
+				return "", err
+			}
+			// The theme colors the lines without colors.
+			if colorLogo != "" {
+				for i, line := range logoLines {
+					if !reANSI.MatchString(line) {
+						logoLines[i] = colorLogo + line + colorNormal
+					}
+				}
 			}
-			infoLines = append(infoLines, tmp)
 		}
+		// Padding each lines with spaces to that each lines is the same length
+		lenLogoLine = padLines(&logoLines)
 	}
 
-	/* ---------- Display the information ---------- */
+	/* ---------- Fit the terminal width ---------- */
+	// The logo is displayed above the information when the values would be
+	// too narrow beside it, and not at all when it is wider than the terminal.
+	width := terminalWidth()
+	padding := getPaddingSize(infoLines)
+	layout := layoutNoLogo
 	if *config.DisplayLogo {
-		var logoLines []string
-
-		data, err := os.ReadFile(*config.Logo)
-		if err != nil {
-			return err
+		layout = chooseLayout(width, lenLogoLine, padding, infoLines, config.Layout.LogoPosition)
+	}
+	if width > 0 {
+		infoWidth := width
+		if layout == layoutSideBySide {
+			infoWidth -= lenLogoLine + logoGap
 		}
-		if len(data) == 0 {
-			return fmt.Errorf("Invalid logo (empty)")
+		infoLines = fitInfoLines(infoLines, infoWidth, padding, config.Overflow)
+	}
+	lines := make([]string, len(infoLines))
+	for i, line := range infoLines {
+		lines[i] = formatInfoLine(line, padding)
+	}
+	// Without colors support, the escape codes (Ex. of the logos, or the dim
+	// unavailable values) are removed.
+	if level == colorLevelNone {
+		for i := range lines {
+			lines[i] = reANSI.ReplaceAllString(lines[i], "")
 		}
-
-		// Let's remove empty lines and comments
-		allLines := strings.Split(string(data), "\n")
-		for _, line := range allLines {
-			if line == "" || strings.HasPrefix(line, "//") {
-				continue
-			}
-			logoLines = append(logoLines, line)
+		for i := range logoLines {
+			logoLines[i] = reANSI.ReplaceAllString(logoLines[i], "")
 		}
-		// Padding each lines with spaces to that each lines is the same length
-		lenLogoLine := padLogoLines(&logoLines)
-
-		/* ---------- Vertically center the logo and the information ---------- */
-		// Here, we want to vertically center the display of
-		// the logo and the information. So we calculate a padding to be added
-		// to the top and bottom of either the logo or the information,
-		// depending on which one is shorter.
-
-		lenLogoLines := len(logoLines)
-		lenInfoLines := len(infoLines)
-		maxLines := max(lenLogoLines, lenInfoLines)
-
-		if lenLogoLines != lenInfoLines {
-			minLines := min(lenLogoLines, lenInfoLines)
-			topPadding := (maxLines - minLines) / 2
-			bottomPadding := maxLines - minLines - topPadding
-			prependInfoArr := make([][]string, 0)
-			appendInfoArr := make([][]string, 0)
-			prependLogoArr := make([]string, 0)
-			appendLogoArr := make([]string, 0)
+	}
 
-			if lenLogoLines > lenInfoLines {
-				emptyLine := []string{"", "", "", ""}
-				for range topPadding {
-					prependInfoArr = append(prependInfoArr, emptyLine)
-				}
-				infoLines = append(prependInfoArr, infoLines...)
-				for range bottomPadding {
-					appendInfoArr = append(appendInfoArr, emptyLine)
-				}
-				infoLines = append(infoLines, appendInfoArr...)
+	/* ---------- Display the information ---------- */
+	switch layout {
+	case layoutSideBySide:
+		// Vertically center the logo and the information
+		maxLines := max(len(logoLines), len(lines))
+		logoLines, logoTop := centerLines(logoLines, maxLines, lenLogoLine)
+		lines, _ = centerLines(lines, maxLines, 0)
+		logoColumn := 0
+		if config.Layout.LogoPosition == positionRight {
+			// The information is padded, so that the logo is aligned on the right.
+			logoColumn = padLines(&lines) + logoGap
+		}
+		for i := range maxLines {
+			if logoColumn > 0 {
+				output.WriteString(strings.TrimRight(fmt.Sprintf("%s%*s%s", lines[i], logoGap, "", logoLines[i]), " ") + "\n")
 			} else {
-				emptyLine := strings.Repeat(" ", lenLogoLine)
-				for range topPadding {
-					prependLogoArr = append(prependLogoArr, emptyLine)
-				}
-				logoLines = append(prependLogoArr, logoLines...)
-				for range bottomPadding {
-					appendLogoArr = append(appendLogoArr, emptyLine)
-				}
-				logoLines = append(logoLines, appendLogoArr...)
+				output.WriteString(fmt.Sprintf("%s%*s%s\n", logoLines[i], logoGap, "", lines[i]))
 			}
 		}
-
-		/* ---------- Prepare the logo and the information ---------- */
-		dynamicPadding := getPaddingSize(infoLines)
-		for i := range maxLines {
-			output.WriteString(fmt.Sprintf("%s  %s%-*s%s%s\n",
-				logoLines[i],
-				infoLines[i][0],
-				dynamicPadding,
-				infoLines[i][1],
-				infoLines[i][2],
-				infoLines[i][3],
-			))
+		if image != nil {
+			output.WriteString(image.draw(logoTop, logoColumn, maxLines))
+		}
+	case layoutStacked:
+		for _, line := range logoLines {
+			output.WriteString(strings.TrimRight(line, " ") + "\n")
+		}
+		output.WriteString("\n")
+		for _, line := range lines {
+			output.WriteString(line + "\n")
+		}
+		if image != nil {
+			output.WriteString(image.draw(0, 0, len(logoLines)+1+len(lines)))
 		}
-	} else {
-		/* ---------- Prepare only the information ---------- */
-		dynamicPadding := getPaddingSize(infoLines)
-		for _, i := range infoLines {
-			output.WriteString(fmt.Sprintf("%s%-*s%s%s\n",
-				i[0],
-				dynamicPadding,
-				i[1],
-				i[2],
-				i[3],
-			))
+	default:
+		for _, line := range lines {
+			output.WriteString(line + "\n")
 		}
 	}
 
-	fmt.Printf("%s", output.String())
-	return nil
+	return output.String(), nil
 }
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"minfo/pkg/sysinfo"
+)
+
+func TestFormatInfoLine(t *testing.T) {
+	tests := []struct {
+		layout   LayoutConfig
+		line     []string
+		expected string
+	}{
+		{LayoutConfig{Separator: " "}, []string{"", "CPU", "", "Apple M4"}, "CPU    Apple M4"},
+		{LayoutConfig{Separator: ": "}, []string{"", "CPU", "", "Apple M4"}, "CPU   : Apple M4"},
+		{LayoutConfig{Separator: ": ", TitleAlign: alignRight}, []string{"", "CPU", "", "Apple M4"}, "   CPU: Apple M4"},
+		// No separator without title (Ex. wrapped values)
+		{LayoutConfig{Separator: " │ "}, []string{"", "", "", "16 cores"}, "         16 cores"},
+		// Header
+		{LayoutConfig{Separator: " "}, []string{"jdoe@jdoe-laptop"}, "jdoe@jdoe-laptop"},
+	}
+	for _, test := range tests {
+		config = &Config{Layout: &test.layout}
+		// Longest title: 6 characters, plus the separator
+		if actual := formatInfoLine(test.line, 6+visibleLength(test.layout.Separator)); actual != test.expected {
+			t.Errorf("Layout %+v: expected %q, got %q", test.layout, test.expected, actual)
+		}
+	}
+}
+
+func TestCreateInfoLines_Sections(t *testing.T) {
+	config = &Config{
+		Items:              []string{"user", "cpu", "os", "memory"},
+		DisplayNerdSymbols: new(bool),
+		Layout:             &LayoutConfig{Separator: " ", Header: true, Sections: true},
+	}
+	hostInfo := &sysinfo.Info{
+		CachedInfo: sysinfo.CachedInfo{
+			Cpu:    &sysinfo.Cpu{Model: "Apple M4", Cores: 10, PerformanceCores: 4, EfficiencyCores: 6},
+			Memory: &sysinfo.Memory{Amount: 16, Unit: "GB"},
+		},
+		User:     &sysinfo.UserInfo{RealName: "John Doe", Login: "jdoe"},
+		Hostname: "jdoe-laptop",
+		Os:       &sysinfo.OsInfo{System: "macOS Sequoia 15.2"},
+	}
+	infoLines, err := createInfoLines(hostInfo, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var titles []string
+	for _, line := range infoLines {
+		if len(line) == 1 {
+			titles = append(titles, reANSI.ReplaceAllString(line[0], ""))
+		} else {
+			titles = append(titles, line[1])
+		}
+	}
+	expected := []string{"jdoe@jdoe-laptop", "----------------",
+		"Hardware", "CPU", "Memory", "", "Software", "OS", "", "Environment", "User"}
+	if !slices.Equal(titles, expected) {
+		t.Errorf("Expected %q, got %q", expected, titles)
+	}
+}
+
+func TestCreateInfoLines_ItemFormatNotMarked(t *testing.T) {
+	hostInfo := &sysinfo.Info{Disk: &sysinfo.DiskInfo{TotalTB: 2, FreeTB: 0.1, SmartStatus: "Verified"}}
+	for _, test := range []struct {
+		itemFormats map[string]string
+		marked      bool
+	}{
+		{nil, true},
+		// The SMART status first: the free space is not on the first line anymore
+		{map[string]string{"disk": "{{.Disk.SmartStatus}}\n{{.Disk.FreeTB}} TB free"}, false},
+	} {
+		config = &Config{
+			Items:              []string{"disk"},
+			DisplayNerdSymbols: new(bool),
+			Layout:             &LayoutConfig{Separator: " "},
+			Thresholds:         defaultThresholds,
+			ItemFormats:        test.itemFormats,
+		}
+		infoLines, err := createInfoLines(hostInfo, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		marked := false
+		for _, line := range infoLines {
+			if strings.Contains(line[3], markerCritical) {
+				marked = true
+			}
+		}
+		if marked != test.marked {
+			t.Errorf("Item formats %v: expected marked %v, got %q", test.itemFormats, test.marked, infoLines)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+/*
+This file contains the HTTP server mode ("minfo serve"): a long-running process
+which re-collects the items on configurable intervals, and serves the latest
+information as JSON (/info.json), as OpenMetrics (/metrics), and its health (/healthz).
+*/
+
+import (
+	"context"
+	"errors"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"minfo/pkg/sysinfo"
+)
+
+// Default configuration of the server (see ServeConfig).
+var (
+	defaultListen        = ":9870"
+	defaultServeInterval = time.Minute
+)
+
+// server holds the latest collected information.
+type server struct {
+	opts      sysinfo.Options
+	intervals map[string]time.Duration // by item
+
+	mu          sync.RWMutex
+	hostInfo    sysinfo.Info
+	fetchErrors sysinfo.Errors
+	collectedAt map[string]time.Time // last collection, by item
+}
+
+func newServer(opts sysinfo.Options, serveConfig *ServeConfig) *server {
+	s := &server{
+		opts:        opts,
+		intervals:   map[string]time.Duration{},
+		fetchErrors: sysinfo.Errors{},
+		collectedAt: map[string]time.Time{},
+	}
+	for _, item := range opts.Items {
+		s.intervals[item] = serveConfig.itemInterval(item)
+	}
+	return s
+}
+
+// dueItems returns the items whose interval has elapsed since their last collection.
+func (s *server) dueItems(now time.Time) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var due []string
+	for _, item := range s.opts.Items {
+		if collectedAt, ok := s.collectedAt[item]; !ok || now.Sub(collectedAt) >= s.intervals[item] {
+			due = append(due, item)
+		}
+	}
+	return due
+}
+
+// collect collects the given items and updates the information served.
+// The static cache and the weather cache are used as by the command line.
+func (s *server) collect(ctx context.Context, items []string) {
+	opts := s.opts
+	opts.Items = items
+	hostInfo, fetchErrors, err := collect(ctx, opts)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		// The previous information is kept, and retried at the next interval.
+		log.Printf("Error collecting %v: %v", items, err)
+	} else {
+		mergeCollection(&s.hostInfo, s.fetchErrors, hostInfo, fetchErrors, items)
+	}
+	now := time.Now()
+	for _, item := range items {
+		s.collectedAt[item] = now
+	}
+	// --refresh only applies to the first collection.
+	s.opts.RefreshCache = false
+}
+
+// run collects the items when they are due, until ctx is done.
+func (s *server) run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if due := s.dueItems(time.Now()); len(due) > 0 {
+			s.collect(ctx, due)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// snapshot returns a copy of the information served.
+func (s *server) snapshot() (*sysinfo.Info, sysinfo.Errors) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	hostInfo := s.hostInfo
+	return &hostInfo, maps.Clone(s.fetchErrors)
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /info.json", func(w http.ResponseWriter, r *http.Request) {
+		jsonData, err := marshalJSON(s.snapshot())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonData)
+	})
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+		hostInfo, fetchErrors := s.snapshot()
+		if err := sysinfo.WriteMetrics(w, hostInfo, fetchErrors); err != nil {
+			log.Printf("Error writing metrics: %v", err)
+		}
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		// Healthy once all the items have been collected at least once.
+		s.mu.RLock()
+		ready := len(s.collectedAt) == len(s.opts.Items)
+		s.mu.RUnlock()
+		if !ready {
+			http.Error(w, "starting", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+	return mux
+}
+
+// runServer runs the HTTP server until SIGINT or SIGTERM is received.
+func runServer(opts sysinfo.Options, serveConfig *ServeConfig) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := newServer(opts, serveConfig)
+	go s.run(ctx)
+
+	httpServer := &http.Server{
+		Addr:              serveConfig.Listen,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Listening on %s", serveConfig.Listen)
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
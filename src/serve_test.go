@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"minfo/pkg/sysinfo"
+)
+
+func TestServer(t *testing.T) {
+	interval := time.Minute
+	serveConfig := &ServeConfig{Interval: &interval, ItemIntervals: map[string]time.Duration{"datetime": time.Second}}
+	s := newServer(sysinfo.Options{Items: []string{"datetime", "terminal"}}, serveConfig)
+	handler := s.handler()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	if code := get("/healthz").Code; code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /healthz to be unavailable before the first collection, got %d", code)
+	}
+
+	now := time.Now()
+	s.collect(context.Background(), s.dueItems(now))
+	if code := get("/healthz").Code; code != http.StatusOK {
+		t.Errorf("Expected /healthz to be OK, got %d", code)
+	}
+	if due := s.dueItems(now.Add(2 * time.Second)); len(due) != 1 || due[0] != "datetime" {
+		t.Errorf("Expected only datetime to be due, got %v", due)
+	}
+
+	var info map[string]any
+	if err := json.Unmarshal(get("/info.json").Body.Bytes(), &info); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if info["datetime"] == nil || info["terminal"] == nil {
+		t.Errorf("Expected datetime and terminal in /info.json, got %v", info)
+	}
+
+	metrics := get("/metrics")
+	if !strings.HasPrefix(metrics.Header().Get("Content-Type"), "application/openmetrics-text") {
+		t.Errorf("Unexpected content type: %s", metrics.Header().Get("Content-Type"))
+	}
+	if !strings.HasSuffix(metrics.Body.String(), "# EOF\n") {
+		t.Errorf("Unexpected metrics:\n%s", metrics.Body.String())
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+/*
+This file contains the color themes of the text output: the colors of the titles,
+values, separators, warning and critical values, and of the logos without colors.
+
+A color is either a truecolor "#RRGGBB", an index of the 256 colors palette,
+or the name of an ANSI color (Ex. "cyan", "bright_red"). It is displayed with
+the best color level of the terminal: truecolors are converted to the 256 and
+16 colors palettes when needed (see logo_convert.go). Without colors support
+(NO_COLOR, output which is not a terminal...), no escape codes are written at all.
+*/
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const defaultThemeName = "default"
+
+// Built-in themes. The colors which are not defined are not displayed.
+var themes = map[string]ThemeConfig{
+	"default": {
+		Title:    "39",
+		Warning:  "yellow",
+		Critical: "red",
+	},
+	"mono": {},
+	"dracula": {
+		Title:     "#8BE9FD",
+		Value:     "#F8F8F2",
+		Separator: "#6272A4",
+		Warning:   "#FFB86C",
+		Critical:  "#FF5555",
+		Logo:      "#FF79C6",
+	},
+	"gruvbox": {
+		Title:     "#83A598",
+		Value:     "#EBDBB2",
+		Separator: "#928374",
+		Warning:   "#FE8019",
+		Critical:  "#FB4934",
+		Logo:      "#B8BB26",
+	},
+	"nord": {
+		Title:     "#88C0D0",
+		Value:     "#D8DEE9",
+		Separator: "#4C566A",
+		Warning:   "#EBCB8B",
+		Critical:  "#BF616A",
+		Logo:      "#81A1C1",
+	},
+	"solarized": {
+		Title:     "#268BD2",
+		Value:     "#93A1A1",
+		Separator: "#586E75",
+		Warning:   "#B58900",
+		Critical:  "#DC322F",
+		Logo:      "#2AA198",
+	},
+}
+
+// SGR codes of the ANSI colors names
+var ansiColorCodes = map[string]int{
+	"black":          30,
+	"red":            31,
+	"green":          32,
+	"yellow":         33,
+	"blue":           34,
+	"magenta":        35,
+	"cyan":           36,
+	"white":          37,
+	"bright_black":   90,
+	"gray":           90,
+	"bright_red":     91,
+	"bright_green":   92,
+	"bright_yellow":  93,
+	"bright_blue":    94,
+	"bright_magenta": 95,
+	"bright_cyan":    96,
+	"bright_white":   97,
+}
+
+// themeNames returns the names of the built-in themes, sorted.
+func themeNames() []string {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// resolveTheme returns the colors of the theme of the configuration:
+// the colors of its built-in theme (default if none), overridden by its own colors.
+func resolveTheme(themeConfig *ThemeConfig) (ThemeConfig, error) {
+	if themeConfig == nil {
+		return themes[defaultThemeName], nil
+	}
+	name := themeConfig.Name
+	if name == "" {
+		name = defaultThemeName
+	}
+	resolved, ok := themes[name]
+	if !ok {
+		return ThemeConfig{}, fmt.Errorf("unknown theme: %s (available: %s)", name, strings.Join(themeNames(), ", "))
+	}
+	for _, c := range []struct{ value, override *string }{
+		{&resolved.Title, &themeConfig.Title},
+		{&resolved.Value, &themeConfig.Value},
+		{&resolved.Separator, &themeConfig.Separator},
+		{&resolved.Warning, &themeConfig.Warning},
+		{&resolved.Critical, &themeConfig.Critical},
+		{&resolved.Logo, &themeConfig.Logo},
+	} {
+		if *c.override != "" {
+			*c.value = *c.override
+		}
+		if _, err := parseThemeColor(*c.value); err != nil {
+			return ThemeConfig{}, err
+		}
+	}
+	return resolved, nil
+}
+
+// applyTheme sets the colors of the text output (colorTitle, colorValue...)
+// from the theme of the configuration, for the color level of the terminal.
+func applyTheme(themeConfig *ThemeConfig, level int) error {
+	resolved, err := resolveTheme(themeConfig)
+	if err != nil {
+		return err
+	}
+	for _, c := range []struct {
+		escape *string
+		value  string
+	}{
+		{&colorTitle, resolved.Title},
+		{&colorValue, resolved.Value},
+		{&colorSeparator, resolved.Separator},
+		{&colorWarning, resolved.Warning},
+		{&colorCritical, resolved.Critical},
+		{&colorLogo, resolved.Logo},
+	} {
+		colors, err := parseThemeColor(c.value)
+		if err != nil {
+			return err
+		}
+		if *c.escape, err = escapeCode(level, colors.Color, colors.Color256, colors.Color16, 38); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseThemeColor returns the colors of a theme color for each color level.
+func parseThemeColor(value string) (logoColors, error) {
+	if value == "" {
+		return logoColors{}, nil
+	}
+	if strings.HasPrefix(value, "#") {
+		r, g, b, err := parseHexColor(value)
+		if err != nil {
+			return logoColors{}, err
+		}
+		c := color.NRGBA{r, g, b, 255}
+		return logoColors{
+			Color:    value,
+			Color256: strconv.Itoa(color256Index(c)),
+			Color16:  strconv.Itoa(color16Code(c, false)),
+		}, nil
+	}
+	if index, err := strconv.Atoi(value); err == nil {
+		var code int
+		switch {
+		case index < 0 || index > 255:
+			return logoColors{}, fmt.Errorf("invalid 256 colors index: %s", value)
+		case index < 8:
+			code = 30 + index
+		case index < 16:
+			code = 90 + index - 8
+		default:
+			code = color16Code(color256(index), false)
+		}
+		return logoColors{Color256: value, Color16: strconv.Itoa(code)}, nil
+	}
+	if code, ok := ansiColorCodes[value]; ok {
+		return logoColors{Color16: strconv.Itoa(code)}, nil
+	}
+	return logoColors{}, fmt.Errorf("invalid color: %s (#RRGGBB, 256 colors index or ANSI color name)", value)
+}
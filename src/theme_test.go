@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseThemeColor(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected logoColors
+	}{
+		{"", logoColors{}},
+		{"#FF0000", logoColors{Color: "#FF0000", Color256: "196", Color16: "91"}},
+		{"39", logoColors{Color256: "39", Color16: "36"}},
+		{"9", logoColors{Color256: "9", Color16: "91"}},
+		{"cyan", logoColors{Color16: "36"}},
+	}
+	for _, test := range tests {
+		actual, err := parseThemeColor(test.value)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if actual != test.expected {
+			t.Errorf("parseThemeColor(%q) = %+v, expected %+v", test.value, actual, test.expected)
+		}
+	}
+	for _, value := range []string{"#12", "256", "purple"} {
+		if _, err := parseThemeColor(value); err == nil {
+			t.Errorf("parseThemeColor(%q): expected an error", value)
+		}
+	}
+}
+
+func TestResolveTheme(t *testing.T) {
+	resolved, err := resolveTheme(&ThemeConfig{Name: "nord", Critical: "bright_red"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resolved.Title != themes["nord"].Title || resolved.Critical != "bright_red" {
+		t.Errorf("Unexpected theme: %+v", resolved)
+	}
+	if _, err := resolveTheme(&ThemeConfig{Name: "unknown"}); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	tests := []struct {
+		level    int
+		expected string
+	}{
+		{colorLevelTrue, "\u001B[38;2;136;192;208m"},
+		{colorLevel256, "\u001B[38;5;110m"},
+		{colorLevel16, "\u001B[37m"},
+		{colorLevelNone, ""},
+	}
+	for _, test := range tests {
+		if err := applyTheme(&ThemeConfig{Name: "nord"}, test.level); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if colorTitle != test.expected {
+			t.Errorf("Level %d: expected %q, got %q", test.level, test.expected, colorTitle)
+		}
+	}
+	// Let's not color the output of the other tests
+	if err := applyTheme(&ThemeConfig{Name: "mono"}, colorLevelNone); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
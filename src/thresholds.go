@@ -0,0 +1,239 @@
+package main
+
+/*
+This file contains the checks of the health values (disk free space, battery
+health, SMART status...), compared to the warning and critical conditions of
+the configuration (see Config.Thresholds).
+
+A condition is an operator followed by a value, Ex. "< 20" or "!= Good".
+The operators <, <=, > and >= compare numbers; == and != compare strings
+(case-insensitive), with alternatives separated by "|", Ex. "!= Good|Normal".
+
+The values which reach a condition are displayed with the warning or critical
+color of the theme and a marker, and the status of the items is added to the
+JSON output.
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"minfo/pkg/sysinfo"
+)
+
+// Markers displayed after the values which reach a condition
+const (
+	markerWarning  = "⚠"
+	markerCritical = "✖"
+)
+
+// Statuses of the items in the JSON output, by level (see bars.go)
+var statusNames = map[int]string{
+	levelNormal:   "ok",
+	levelWarning:  "warning",
+	levelCritical: "critical",
+}
+
+// check is a value of an item, compared to its thresholds.
+type check struct {
+	item string
+	line int // line of the item displaying the value (default lines, not the item_formats templates)
+	// value returns the checked value, or false if it is not available.
+	value func(hostInfo *sysinfo.Info) (string, bool)
+}
+
+// Values which can be checked, by name (see Config.Thresholds)
+var checks = map[string]check{
+	"disk_free": {item: "disk", line: 0, value: func(hostInfo *sysinfo.Info) (string, bool) {
+		if hostInfo.Disk == nil || hostInfo.Disk.TotalTB == 0 {
+			return "", false
+		}
+		return fmt.Sprintf("%.1f", 100*hostInfo.Disk.FreeTB/hostInfo.Disk.TotalTB), true
+	}},
+	"smart_status": {item: "disk", line: 1, value: func(hostInfo *sysinfo.Info) (string, bool) {
+		if hostInfo.Disk == nil || hostInfo.Disk.SmartStatus == "" {
+			return "", false
+		}
+		return hostInfo.Disk.SmartStatus, true
+	}},
+	"battery_charge": {item: "battery", line: 0, value: func(hostInfo *sysinfo.Info) (string, bool) {
+		if hostInfo.Battery == nil {
+			return "", false
+		}
+		return strconv.Itoa(hostInfo.Battery.StatusPercent), true
+	}},
+	"battery_capacity": {item: "battery", line: 0, value: func(hostInfo *sysinfo.Info) (string, bool) {
+		if hostInfo.Battery == nil {
+			return "", false
+		}
+		return strconv.Itoa(hostInfo.Battery.CapacityPercent), true
+	}},
+	"battery_health": {item: "battery", line: 1, value: func(hostInfo *sysinfo.Info) (string, bool) {
+		if hostInfo.Battery == nil {
+			return "", false
+		}
+		return hostInfo.Battery.Health, true
+	}},
+	"memory_used": {item: "memory_usage", line: 0, value: func(hostInfo *sysinfo.Info) (string, bool) {
+		if hostInfo.MemoryUsage == nil {
+			return "", false
+		}
+		return strconv.FormatFloat(hostInfo.MemoryUsage.UsedPercent, 'f', -1, 64), true
+	}},
+	"memory_pressure": {item: "memory_usage", line: 0, value: func(hostInfo *sysinfo.Info) (string, bool) {
+		if hostInfo.MemoryUsage == nil || hostInfo.MemoryUsage.Pressure == "" {
+			return "", false
+		}
+		return hostInfo.MemoryUsage.Pressure, true
+	}},
+	"system_integrity": {item: "system_integrity", line: 0, value: func(hostInfo *sysinfo.Info) (string, bool) {
+		if hostInfo.SystemIntegrity == "" {
+			return "", false
+		}
+		return strings.TrimPrefix(hostInfo.SystemIntegrity, "integrity_"), true
+	}},
+}
+
+// Default thresholds, overridden check by check by the configuration
+var defaultThresholds = map[string]ThresholdConfig{
+	"disk_free":        {Warning: "< 20", Critical: "< 10"},
+	"smart_status":     {Critical: "!= Verified"},
+	"battery_capacity": {Warning: "< 80"},
+	"battery_health":   {Warning: "!= Good|Normal|Unknown"},
+	"memory_pressure":  {Warning: "== warning", Critical: "== critical"},
+	"system_integrity": {Warning: "!= enabled"},
+}
+
+// Operators of the conditions, the longest first
+var conditionOperators = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// condition is a parsed warning or critical condition, Ex. "< 20".
+type condition struct {
+	operator string
+	operand  string
+	number   float64 // operand of the numeric operators
+}
+
+// parseCondition parses a condition (see the file comment).
+func parseCondition(s string) (*condition, error) {
+	s = strings.TrimSpace(s)
+	for _, operator := range conditionOperators {
+		operand, ok := strings.CutPrefix(s, operator)
+		if !ok {
+			continue
+		}
+		c := &condition{operator: operator, operand: strings.TrimSpace(operand)}
+		if c.operand == "" {
+			return nil, fmt.Errorf("invalid condition: %s (no value)", s)
+		}
+		if operator != "==" && operator != "!=" {
+			number, err := strconv.ParseFloat(c.operand, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid condition: %s (%s needs a number)", s, operator)
+			}
+			c.number = number
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("invalid condition: %s (operators: %s)", s, strings.Join(conditionOperators, " "))
+}
+
+// matches returns true if the value reaches the condition.
+func (c *condition) matches(value string) bool {
+	switch c.operator {
+	case "==", "!=":
+		equal := false
+		for _, alternative := range strings.Split(c.operand, "|") {
+			if strings.EqualFold(strings.TrimSpace(alternative), value) {
+				equal = true
+			}
+		}
+		return equal == (c.operator == "==")
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	switch c.operator {
+	case "<":
+		return number < c.number
+	case "<=":
+		return number <= c.number
+	case ">":
+		return number > c.number
+	}
+	return number >= c.number
+}
+
+// validate checks the conditions of the thresholds.
+func (t ThresholdConfig) validate() error {
+	for _, s := range []string{t.Warning, t.Critical} {
+		if s == "" {
+			continue
+		}
+		if _, err := parseCondition(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// level returns the level of the value: critical, warning or normal.
+// The conditions have been validated when loading the configuration.
+func (t ThresholdConfig) level(value string) int {
+	for _, c := range []struct {
+		condition string
+		level     int
+	}{{t.Critical, levelCritical}, {t.Warning, levelWarning}} {
+		if c.condition == "" {
+			continue
+		}
+		if parsed, err := parseCondition(c.condition); err == nil && parsed.matches(value) {
+			return c.level
+		}
+	}
+	return levelNormal
+}
+
+// checkLevels returns the levels of the lines of the items which are checked
+// (by item, then by line), the worst level of a line being kept.
+func checkLevels(hostInfo *sysinfo.Info) map[string]map[int]int {
+	levels := map[string]map[int]int{}
+	for name, thresholds := range config.Thresholds {
+		c := checks[name]
+		value, ok := c.value(hostInfo)
+		if !ok {
+			continue
+		}
+		if levels[c.item] == nil {
+			levels[c.item] = map[int]int{}
+		}
+		levels[c.item][c.line] = max(levels[c.item][c.line], thresholds.level(value))
+	}
+	return levels
+}
+
+// itemStatuses returns the status (ok, warning or critical) of the items which are checked.
+func itemStatuses(hostInfo *sysinfo.Info) map[string]string {
+	statuses := map[string]string{}
+	for item, lines := range checkLevels(hostInfo) {
+		level := levelNormal
+		for _, lineLevel := range lines {
+			level = max(level, lineLevel)
+		}
+		statuses[item] = statusNames[level]
+	}
+	return statuses
+}
+
+// markLine colors the value of a line with the color of the level, followed by its marker.
+func markLine(line infoLine, level int) infoLine {
+	switch level {
+	case levelWarning:
+		line.Value = colored(colorWarning, line.Value+" "+markerWarning)
+	case levelCritical:
+		line.Value = colored(colorCritical, line.Value+" "+markerCritical)
+	}
+	return line
+}
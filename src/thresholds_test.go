@@ -0,0 +1,60 @@
+package main
+
+import (
+	"maps"
+	"testing"
+
+	"minfo/pkg/sysinfo"
+)
+
+func TestConditionMatches(t *testing.T) {
+	tests := []struct {
+		condition string
+		value     string
+		expected  bool
+	}{
+		{"< 20", "12.5", true},
+		{"< 20", "20", false},
+		{"<= 20", "20", true},
+		{">80", "81", true},
+		{">= 80", "abc", false},
+		{"== good", "Good", true},
+		{"!= Good|Normal", "Normal", false},
+		{"!= Good|Normal", "Service Recommended", true},
+	}
+	for _, test := range tests {
+		c, err := parseCondition(test.condition)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if actual := c.matches(test.value); actual != test.expected {
+			t.Errorf("%q matches %q: expected %v", test.condition, test.value, test.expected)
+		}
+	}
+	for _, condition := range []string{"20", "< abc", "=="} {
+		if _, err := parseCondition(condition); err == nil {
+			t.Errorf("parseCondition(%q): expected an error", condition)
+		}
+	}
+}
+
+func TestItemStatuses(t *testing.T) {
+	config = &Config{Thresholds: defaultThresholds}
+	hostInfo := &sysinfo.Info{
+		Disk:            &sysinfo.DiskInfo{TotalTB: 2, FreeTB: 0.3, SmartStatus: "Verified"},
+		Battery:         &sysinfo.BatteryInfo{StatusPercent: 50, CapacityPercent: 85, Health: "Good"},
+		SystemIntegrity: "integrity_disabled",
+	}
+	expected := map[string]string{"disk": "warning", "battery": "ok", "system_integrity": "warning"}
+	if actual := itemStatuses(hostInfo); !maps.Equal(actual, expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+
+	// The worst level of a line is kept.
+	hostInfo.Disk.SmartStatus = "Failing"
+	hostInfo.Battery.CapacityPercent = 70
+	levels := checkLevels(hostInfo)
+	if levels["disk"][0] != levelWarning || levels["disk"][1] != levelCritical || levels["battery"][0] != levelWarning {
+		t.Errorf("Unexpected levels: %v", levels)
+	}
+}
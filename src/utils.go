@@ -1,27 +1,12 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
-	"errors"
 	"fmt"
 	"math"
-	"os"
-	"os/exec"
-	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 )
 
-// runCommand runs a command and returns the output
-func runCommand(cmd *exec.Cmd) (string, error) {
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err := cmd.Run()
-	return out.String(), err
-}
-
 // capitalizeFirstLetter capitalizes the first letter of a string
 func capitalizeFirstLetter(s string) string {
 	if len(s) == 0 {
@@ -30,27 +15,6 @@ func capitalizeFirstLetter(s string) string {
 	return strings.ToUpper(string(s[0])) + s[1:]
 }
 
-// This function replicates the functionality of the "which" command
-func which(command string) (string, error) {
-	pathEnv := os.Getenv("PATH")
-	if pathEnv == "" {
-		return "", errors.New("PATH environment variable is empty")
-	}
-	paths := filepath.SplitList(pathEnv)
-
-	for _, dir := range paths {
-		fullPath := filepath.Join(dir, command)
-
-		if fileInfo, err := os.Stat(fullPath); err == nil {
-			if !fileInfo.IsDir() && (fileInfo.Mode()&0111 != 0) { // Check for executable bit
-				return fullPath, nil
-			}
-		}
-	}
-
-	return "", fmt.Errorf("%s: command not found", command)
-}
-
 // Helper to get a line from a 2D slice, or an empty line if out of range
 func getLine(lines [][]string, index int) string {
 	if index >= 0 && index < len(lines) {
@@ -75,6 +39,14 @@ func min(a, b int) int {
 	return b
 }
 
+// This function returns the absolute value of an integer
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
 // This function rounds a float to the nearest half.
 // (Using this to display temperatures in Celsius to nearest 0.5)
 func roundToNearestHalf(x float64) float64 {
@@ -93,6 +65,18 @@ func formatFloat(x float64) string {
 	return s
 }
 
+// formatMemory formats an amount of memory like macOS does: in GB of 1024^3 bytes, Ex. "24.5 GB".
+func formatMemory(bytes uint64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	n := float64(bytes)
+	i := 0
+	for n >= 1024 && i < len(units)-1 {
+		n /= 1024
+		i++
+	}
+	return fmt.Sprintf("%s %s", formatFloat(n), units[i])
+}
+
 // This functions returns only the unique strings in a slice of strings
 func uniqueStrings(input []string) []string {
 	seen := make(map[string]bool)
@@ -108,43 +92,7 @@ func uniqueStrings(input []string) []string {
 	return result
 }
 
-func ensureDirExists(dirPath string) error {
-	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
-		err := os.MkdirAll(dirPath, os.ModePerm)
-		if err != nil {
-			return fmt.Errorf("failed to create directory: %v", err)
-		}
-	} else if err != nil {
-		return fmt.Errorf("failed to check directory: %v", err)
-	}
-	return nil
-}
-
-// isFileOlderThan checks if a file's modification time is older than the given duration.
-func isFileOlderThan(filePath string, duration time.Duration) (bool, error) {
-	info, err := os.Stat(filePath)
-	if err != nil {
-		return false, fmt.Errorf("failed to get file info: %v", err)
-	}
-	modTime := info.ModTime()
-
-	return time.Since(modTime) > duration, nil
-}
-
 func windArrow(deg int) string {
 	arrows := []string{"↓", "↙", "←", "↖", "↑", "↗", "→", "↘"}
 	return arrows[((deg+22)%360)/45]
 }
-
-// countNonEmptyLines returns the number of non-empty trimmed lines in the provided string.
-func countNonEmptyLines(input string) int {
-	scanner := bufio.NewScanner(strings.NewReader(input))
-	count := 0
-	for scanner.Scan() {
-		if strings.TrimSpace(scanner.Text()) == "" {
-			continue
-		}
-		count++
-	}
-	return count
-}
@@ -0,0 +1,104 @@
+package main
+
+/*
+This file contains the watch mode (--watch): the information is displayed
+again on an interval, redrawn in place (or as NDJSON with --json).
+Only the volatile items are fetched again at each interval.
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"minfo/pkg/sysinfo"
+
+	"golang.org/x/term"
+)
+
+// Items whose information changes over time, fetched again at each interval.
+var volatileItems = []string{"battery", "disk", "volumes", "memory_usage", "uptime", "datetime", "weather"}
+
+const (
+	ansiCursorUp   = "\u001B[%dA"
+	ansiClearToEnd = "\u001B[J"
+	ansiHideCursor = "\u001B[?25l"
+	ansiShowCursor = "\u001B[?25h"
+)
+
+// watch displays the information, then fetches the volatile items
+// and displays the information again on each interval, until SIGINT or SIGTERM.
+func watch(opts sysinfo.Options, interval time.Duration, cmdLine *cmdLineParams) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	hostInfo, fetchErrors, err := collect(ctx, opts)
+	if err != nil {
+		return err
+	}
+	var toRefresh []string
+	for _, item := range opts.Items {
+		if slices.Contains(volatileItems, item) {
+			toRefresh = append(toRefresh, item)
+		}
+	}
+	refreshOpts := opts
+	refreshOpts.Items = toRefresh
+	refreshOpts.RefreshCache = false
+
+	// The text is redrawn in place only on a terminal.
+	inPlace := cmdLine.Output == outputText && term.IsTerminal(int(os.Stdout.Fd()))
+	if inPlace {
+		fmt.Print(ansiHideCursor)
+		defer fmt.Print(ansiShowCursor)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	previousLines := 0
+	for {
+		if cmdLine.Output == outputJson {
+			// NDJSON: one compact JSON object per line
+			jsonData, err := json.Marshal(newJSONOutput(hostInfo, fetchErrors))
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(jsonData))
+		} else {
+			output, err := renderInfo(hostInfo, fetchErrors)
+			if err != nil {
+				return err
+			}
+			if inPlace && previousLines > 0 {
+				fmt.Printf(ansiCursorUp+ansiClearToEnd, previousLines)
+			} else if previousLines > 0 {
+				fmt.Println()
+			}
+			fmt.Print(output)
+			previousLines = strings.Count(output, "\n")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if len(toRefresh) == 0 {
+			continue
+		}
+		newInfo, newErrors, err := collect(ctx, refreshOpts)
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		mergeCollection(hostInfo, fetchErrors, newInfo, newErrors, toRefresh)
+	}
+}